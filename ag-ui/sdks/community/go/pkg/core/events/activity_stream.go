@@ -0,0 +1,145 @@
+package events
+
+import (
+	"sync"
+)
+
+// Event is the common interface implemented by every AG-UI event.
+type Event interface {
+	Type() EventType
+	Validate() error
+	ToJSON() ([]byte, error)
+}
+
+// ActivityStreamConfig controls when ActivityStream falls back to a full
+// snapshot instead of a delta.
+type ActivityStreamConfig struct {
+	// MaxPatchOps caps the number of patch operations; a diff larger
+	// than this is sent as a snapshot instead. Zero means no cap.
+	MaxPatchOps int
+	// MaxPatchRatio caps patch size relative to the document: a diff
+	// whose op count exceeds MaxPatchRatio * (number of leaf values in
+	// the document) is sent as a snapshot instead. Zero means no cap.
+	MaxPatchRatio float64
+}
+
+// DefaultActivityStreamConfig returns reasonable defaults: snapshot once
+// a patch exceeds 20 ops or touches more than half the document.
+func DefaultActivityStreamConfig() ActivityStreamConfig {
+	return ActivityStreamConfig{MaxPatchOps: 20, MaxPatchRatio: 0.5}
+}
+
+// ActivityStream remembers the last content seen for each MessageID and
+// ActivityType and turns successive Set calls into snapshot-or-delta
+// events: the first Set for a given key always yields a snapshot, later
+// calls yield a delta unless the patch is large enough (per config)
+// that a fresh snapshot is cheaper to apply.
+type ActivityStream struct {
+	config ActivityStreamConfig
+
+	mu   sync.Mutex
+	last map[string]any
+}
+
+// NewActivityStream creates an ActivityStream using config.
+func NewActivityStream(config ActivityStreamConfig) *ActivityStream {
+	return &ActivityStream{
+		config: config,
+		last:   make(map[string]any),
+	}
+}
+
+// Set records content as the current state for messageID+activityType
+// and returns the event to emit: an *ActivitySnapshotEvent the first
+// time a key is seen, or whenever the computed patch is too large, and
+// an *ActivityDeltaEvent otherwise. It returns nil, nil if content is
+// unchanged from the last call.
+//
+// The read of the previous snapshot, the diff against content, and the
+// write of the new snapshot all happen under a single lock acquisition
+// so that two concurrent Set calls for the same key can never both diff
+// against the same baseline and emit deltas that, applied in sequence,
+// diverge from what ActivityStream itself remembers.
+func (s *ActivityStream) Set(messageID, activityType string, content any) (Event, error) {
+	key := activityStreamKey(messageID, activityType)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev, known := s.last[key]
+
+	if !known {
+		s.rememberSnapshotLocked(key, content)
+		return NewActivitySnapshotEvent(messageID, activityType, content), nil
+	}
+
+	patch, err := DiffActivity(prev, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(patch) == 0 {
+		return nil, nil
+	}
+
+	s.rememberSnapshotLocked(key, content)
+
+	if s.exceedsThreshold(patch, content) {
+		return NewActivitySnapshotEvent(messageID, activityType, content), nil
+	}
+	return NewActivityDeltaEvent(messageID, activityType, patch), nil
+}
+
+// rememberSnapshotLocked stores content as the current snapshot for key.
+// Callers must hold s.mu.
+func (s *ActivityStream) rememberSnapshotLocked(key string, content any) {
+	generic, err := toGeneric(content)
+	if err != nil {
+		generic = content
+	}
+	s.last[key] = generic
+}
+
+func (s *ActivityStream) exceedsThreshold(patch []JSONPatchOperation, content any) bool {
+	if s.config.MaxPatchOps > 0 && len(patch) > s.config.MaxPatchOps {
+		return true
+	}
+	if s.config.MaxPatchRatio > 0 {
+		size := countLeaves(content)
+		if size > 0 && float64(len(patch))/float64(size) > s.config.MaxPatchRatio {
+			return true
+		}
+	}
+	return false
+}
+
+func activityStreamKey(messageID, activityType string) string {
+	return messageID + "\x00" + activityType
+}
+
+// countLeaves returns the number of scalar (non-object, non-array)
+// values reachable from v, used as a rough measure of document size
+// when deciding whether a patch is "too large" relative to the whole
+// document.
+func countLeaves(v any) int {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return 0
+	}
+
+	switch val := generic.(type) {
+	case map[string]any:
+		total := 0
+		for _, child := range val {
+			total += countLeaves(child)
+		}
+		return total
+	case []any:
+		total := 0
+		for _, child := range val {
+			total += countLeaves(child)
+		}
+		return total
+	default:
+		return 1
+	}
+}
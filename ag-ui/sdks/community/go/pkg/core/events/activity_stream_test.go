@@ -0,0 +1,111 @@
+package events
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivityStream_Set_FirstCallYieldsSnapshot(t *testing.T) {
+	stream := NewActivityStream(DefaultActivityStreamConfig())
+
+	event, err := stream.Set("msg-1", "PLAN", map[string]any{"status": "draft"})
+	require.NoError(t, err)
+	_, ok := event.(*ActivitySnapshotEvent)
+	assert.True(t, ok)
+}
+
+func TestActivityStream_UnchangedContentYieldsNil(t *testing.T) {
+	stream := NewActivityStream(DefaultActivityStreamConfig())
+	content := map[string]any{"status": "draft"}
+
+	_, err := stream.Set("msg-1", "PLAN", content)
+	require.NoError(t, err)
+
+	event, err := stream.Set("msg-1", "PLAN", content)
+	require.NoError(t, err)
+	assert.Nil(t, event)
+}
+
+func TestActivityStream_ConcurrentSetsSerializeReadDiffWrite(t *testing.T) {
+	stream := NewActivityStream(ActivityStreamConfig{})
+	base := map[string]any{"status": "draft"}
+
+	_, err := stream.Set("msg-1", "PLAN", base)
+	require.NoError(t, err)
+
+	contentA := map[string]any{"status": "draft", "owner": "alice"}
+	contentB := map[string]any{"status": "draft", "owner": "bob"}
+
+	var wg sync.WaitGroup
+	var eventA, eventB Event
+	var errA, errB error
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		eventA, errA = stream.Set("msg-1", "PLAN", contentA)
+	}()
+	go func() {
+		defer wg.Done()
+		eventB, errB = stream.Set("msg-1", "PLAN", contentB)
+	}()
+	wg.Wait()
+
+	require.NoError(t, errA)
+	require.NoError(t, errB)
+
+	stream.mu.Lock()
+	final := stream.last[activityStreamKey("msg-1", "PLAN")]
+	stream.mu.Unlock()
+
+	// Whichever call the lock let run first diffed against base and
+	// its patch must still apply cleanly to base; the call that ran
+	// second must have diffed against the first call's content (not
+	// against base again), so applying its patch on top of the first
+	// call's content must land exactly on the remembered final state.
+	firstContent, firstPatch := contentA, patchOf(t, eventA)
+	secondContent, secondPatch := contentB, patchOf(t, eventB)
+	if applies(t, base, firstPatch, firstContent) {
+		// firstContent ran first as expected.
+	} else {
+		firstContent, firstPatch = contentB, patchOf(t, eventB)
+		secondContent, secondPatch = contentA, patchOf(t, eventA)
+		require.True(t, applies(t, base, firstPatch, firstContent), "neither call's patch applies cleanly to base")
+	}
+
+	got, err := ApplyPatch(firstContent, secondPatch)
+	require.NoError(t, err)
+	finalG, err := toGeneric(secondContent)
+	require.NoError(t, err)
+	assert.Equal(t, finalG, got)
+	assert.Equal(t, final, got)
+}
+
+// patchOf extracts the patch ops from an ActivityDeltaEvent, or nil if
+// event is a snapshot (whose "patch" is simply replacing the document).
+func patchOf(t *testing.T, event Event) []JSONPatchOperation {
+	t.Helper()
+	delta, ok := event.(*ActivityDeltaEvent)
+	if !ok {
+		return nil
+	}
+	return delta.Patch
+}
+
+// applies reports whether patch, applied to prev, yields content.
+func applies(t *testing.T, prev any, patch []JSONPatchOperation, content any) bool {
+	t.Helper()
+	if patch == nil {
+		return false
+	}
+	got, err := ApplyPatch(prev, patch)
+	if err != nil {
+		return false
+	}
+	contentG, err := toGeneric(content)
+	require.NoError(t, err)
+	return assert.ObjectsAreEqual(contentG, got)
+}
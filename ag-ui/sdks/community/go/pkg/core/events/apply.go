@@ -0,0 +1,330 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ApplyPatch applies an RFC 6902 JSON Patch to doc and returns the
+// resulting document. doc is first round-tripped through encoding/json
+// so that structs, maps, and already-generic values (map[string]any,
+// []any) are all accepted uniformly; the result is returned as the
+// equivalent generic value (map[string]any / []any / scalars).
+func ApplyPatch(doc any, patch []JSONPatchOperation) (any, error) {
+	root, err := toGeneric(doc)
+	if err != nil {
+		return nil, fmt.Errorf("ApplyPatch: %w", err)
+	}
+
+	for i, op := range patch {
+		if err := validateJSONPatchOperation(op); err != nil {
+			return nil, fmt.Errorf("ApplyPatch: invalid operation at index %d: %w", i, err)
+		}
+
+		var applyErr error
+		root, applyErr = applyOne(root, op)
+		if applyErr != nil {
+			return nil, fmt.Errorf("ApplyPatch: operation %d (%s %s): %w", i, op.Op, op.Path, applyErr)
+		}
+	}
+
+	return root, nil
+}
+
+func applyOne(root any, op JSONPatchOperation) (any, error) {
+	switch op.Op {
+	case "add":
+		tokens, err := splitPointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return insertAt(root, tokens, op.Value)
+	case "replace":
+		tokens, err := splitPointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := navigate(root, op.Path); err != nil {
+			return nil, err
+		}
+		return replaceAt(root, tokens, op.Value)
+	case "remove":
+		tokens, err := splitPointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		updated, _, err := removeAt(root, tokens)
+		return updated, err
+	case "move":
+		value, err := navigate(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		fromTokens, err := splitPointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		root, _, err = removeAt(root, fromTokens)
+		if err != nil {
+			return nil, err
+		}
+		toTokens, err := splitPointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return insertAt(root, toTokens, value)
+	case "copy":
+		value, err := navigate(root, op.From)
+		if err != nil {
+			return nil, err
+		}
+		toTokens, err := splitPointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+		return insertAt(root, toTokens, cloneValue(value))
+	case "test":
+		value, err := navigate(root, op.Path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(value, toComparable(op.Value)) {
+			return nil, fmt.Errorf("test failed: value at %q does not match", op.Path)
+		}
+		return root, nil
+	default:
+		return nil, fmt.Errorf("unsupported op %q", op.Op)
+	}
+}
+
+// insertAt adds value at the location named by tokens, creating a new
+// object member or inserting a new array element (RFC 6902 "add"
+// semantics, including "-" to append).
+func insertAt(doc any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	head, rest := tokens[0], tokens[1:]
+	switch v := doc.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			out := cloneMap(v)
+			out[head] = value
+			return out, nil
+		}
+		child, ok := v[head]
+		if !ok {
+			return nil, fmt.Errorf("path segment %q not found", head)
+		}
+		newChild, err := insertAt(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		out := cloneMap(v)
+		out[head] = newChild
+		return out, nil
+	case []any:
+		idx, err := arrayIndex(head, len(v), true)
+		if err != nil {
+			return nil, err
+		}
+		if len(rest) == 0 {
+			if idx > len(v) {
+				return nil, fmt.Errorf("array index %d out of range", idx)
+			}
+			out := make([]any, 0, len(v)+1)
+			out = append(out, v[:idx]...)
+			out = append(out, value)
+			out = append(out, v[idx:]...)
+			return out, nil
+		}
+		if idx >= len(v) {
+			return nil, fmt.Errorf("array index %d out of range", idx)
+		}
+		newChild, err := insertAt(v[idx], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		out := append([]any(nil), v...)
+		out[idx] = newChild
+		return out, nil
+	default:
+		return nil, fmt.Errorf("cannot add into %T", doc)
+	}
+}
+
+// replaceAt overwrites the value at the location named by tokens. The
+// caller must have already confirmed the location exists.
+func replaceAt(doc any, tokens []string, value any) (any, error) {
+	if len(tokens) == 0 {
+		return value, nil
+	}
+
+	head, rest := tokens[0], tokens[1:]
+	switch v := doc.(type) {
+	case map[string]any:
+		child, ok := v[head]
+		if !ok {
+			return nil, fmt.Errorf("path segment %q not found", head)
+		}
+		newChild, err := replaceAt(child, rest, value)
+		if err != nil {
+			return nil, err
+		}
+		out := cloneMap(v)
+		out[head] = newChild
+		return out, nil
+	case []any:
+		idx, err := arrayIndex(head, len(v), false)
+		if err != nil {
+			return nil, err
+		}
+		if idx >= len(v) {
+			return nil, fmt.Errorf("array index %d out of range", idx)
+		}
+		newChild, err := replaceAt(v[idx], rest, value)
+		if err != nil {
+			return nil, err
+		}
+		out := append([]any(nil), v...)
+		out[idx] = newChild
+		return out, nil
+	default:
+		return nil, fmt.Errorf("cannot replace into %T", doc)
+	}
+}
+
+// removeAt deletes the value at the location named by tokens, returning
+// the updated document and the value that was removed.
+func removeAt(doc any, tokens []string) (any, any, error) {
+	if len(tokens) == 0 {
+		return nil, nil, fmt.Errorf("cannot remove the document root")
+	}
+
+	head, rest := tokens[0], tokens[1:]
+	switch v := doc.(type) {
+	case map[string]any:
+		if len(rest) == 0 {
+			removed, ok := v[head]
+			if !ok {
+				return nil, nil, fmt.Errorf("path segment %q not found", head)
+			}
+			out := cloneMap(v)
+			delete(out, head)
+			return out, removed, nil
+		}
+		child, ok := v[head]
+		if !ok {
+			return nil, nil, fmt.Errorf("path segment %q not found", head)
+		}
+		newChild, removed, err := removeAt(child, rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		out := cloneMap(v)
+		out[head] = newChild
+		return out, removed, nil
+	case []any:
+		idx, err := arrayIndex(head, len(v), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if idx >= len(v) {
+			return nil, nil, fmt.Errorf("array index %d out of range", idx)
+		}
+		if len(rest) == 0 {
+			removed := v[idx]
+			out := make([]any, 0, len(v)-1)
+			out = append(out, v[:idx]...)
+			out = append(out, v[idx+1:]...)
+			return out, removed, nil
+		}
+		newChild, removed, err := removeAt(v[idx], rest)
+		if err != nil {
+			return nil, nil, err
+		}
+		out := append([]any(nil), v...)
+		out[idx] = newChild
+		return out, removed, nil
+	default:
+		return nil, nil, fmt.Errorf("cannot remove from %T", doc)
+	}
+}
+
+func arrayIndex(token string, length int, allowAppend bool) (int, error) {
+	if token == "-" {
+		if !allowAppend {
+			return 0, fmt.Errorf(`"-" is not valid here`)
+		}
+		return length, nil
+	}
+
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return idx, nil
+}
+
+func cloneMap(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func cloneValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, child := range val {
+			out[k] = cloneValue(child)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, child := range val {
+			out[i] = cloneValue(child)
+		}
+		return out
+	default:
+		return val
+	}
+}
+
+// toGeneric round-trips v through encoding/json, yielding the
+// map[string]any / []any / scalar representation the patch functions
+// operate on.
+func toGeneric(v any) (any, error) {
+	switch v.(type) {
+	case nil, string, bool, float64:
+		return v, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// toComparable normalizes a value (typically a JSONPatchOperation.Value
+// taken from caller-constructed Go literals such as int) the same way
+// toGeneric would after an encoding/json round trip, so "test" can
+// compare it against a value read back out of the document.
+func toComparable(v any) any {
+	generic, err := toGeneric(v)
+	if err != nil {
+		return v
+	}
+	return generic
+}
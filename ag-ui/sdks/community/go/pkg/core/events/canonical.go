@@ -0,0 +1,124 @@
+package events
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events/canonical"
+)
+
+// CanonicalHash returns the SHA-256 digest of v's JSON Canonicalization
+// Scheme (RFC 8785) encoding. Because JCS output is independent of
+// source key order and numeric formatting, the hash is stable across
+// re-marshaling by intermediaries, which makes it safe to use for
+// content addressing, deduplication, and detached signatures.
+func CanonicalHash(v any) ([32]byte, error) {
+	data, err := canonical.MarshalCanonical(v)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+// ContentID returns a hex-encoded CanonicalHash, suitable for use as a
+// deterministic, content-addressable identifier.
+func ContentID(v any) (string, error) {
+	sum, err := CanonicalHash(v)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sum), nil
+}
+
+// Sign computes a detached ed25519 signature over v's canonical form.
+// Because the signature covers the canonical encoding rather than the
+// original bytes, it still verifies after v has been re-marshaled by an
+// intermediary.
+func Sign(priv ed25519.PrivateKey, v any) ([]byte, error) {
+	data, err := canonical.MarshalCanonical(v)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.Sign(priv, data), nil
+}
+
+// Verify reports whether sig is a valid detached signature over v's
+// canonical form, as produced by Sign.
+func Verify(pub ed25519.PublicKey, v any, sig []byte) (bool, error) {
+	data, err := canonical.MarshalCanonical(v)
+	if err != nil {
+		return false, err
+	}
+	return ed25519.Verify(pub, data, sig), nil
+}
+
+// CanonicalHash returns the event's content hash, computed over its
+// canonical JSON form.
+func (e *BaseEvent) CanonicalHash() ([32]byte, error) {
+	return CanonicalHash(e)
+}
+
+// ContentID returns a deterministic, content-addressable identifier for
+// the event, derived from CanonicalHash.
+func (e *BaseEvent) ContentID() (string, error) {
+	return ContentID(e)
+}
+
+// CanonicalHash returns the event's content hash, computed over its
+// canonical JSON form.
+func (e *ActivitySnapshotEvent) CanonicalHash() ([32]byte, error) {
+	return CanonicalHash(e)
+}
+
+// ContentID returns a deterministic, content-addressable identifier for
+// the event, derived from CanonicalHash.
+func (e *ActivitySnapshotEvent) ContentID() (string, error) {
+	return ContentID(e)
+}
+
+// Sign computes a detached signature over the event's canonical form.
+func (e *ActivitySnapshotEvent) Sign(priv ed25519.PrivateKey) ([]byte, error) {
+	return Sign(priv, e)
+}
+
+// Verify checks a detached signature produced by Sign.
+func (e *ActivitySnapshotEvent) Verify(pub ed25519.PublicKey, sig []byte) (bool, error) {
+	return Verify(pub, e, sig)
+}
+
+// CanonicalHash returns the event's content hash, computed over its
+// canonical JSON form.
+func (e *ActivityDeltaEvent) CanonicalHash() ([32]byte, error) {
+	return CanonicalHash(e)
+}
+
+// ContentID returns a deterministic, content-addressable identifier for
+// the event, derived from CanonicalHash.
+func (e *ActivityDeltaEvent) ContentID() (string, error) {
+	return ContentID(e)
+}
+
+// Sign computes a detached signature over the event's canonical form.
+func (e *ActivityDeltaEvent) Sign(priv ed25519.PrivateKey) ([]byte, error) {
+	return Sign(priv, e)
+}
+
+// Verify checks a detached signature produced by Sign.
+func (e *ActivityDeltaEvent) Verify(pub ed25519.PublicKey, sig []byte) (bool, error) {
+	return Verify(pub, e, sig)
+}
+
+// CanonicalHash returns the message's content hash, computed over its
+// canonical JSON form. Two messages with equal content hash the same
+// even if a map-typed field like ActivityContent was re-marshaled in a
+// different key order by an intermediary.
+func (m Message) CanonicalHash() ([32]byte, error) {
+	return CanonicalHash(m)
+}
+
+// ContentID returns a deterministic, content-addressable identifier for
+// the message, derived from CanonicalHash.
+func (m Message) ContentID() (string, error) {
+	return ContentID(m)
+}
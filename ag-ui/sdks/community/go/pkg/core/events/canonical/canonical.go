@@ -0,0 +1,233 @@
+// Package canonical implements the JSON Canonicalization Scheme (JCS,
+// RFC 8785): a deterministic re-serialization of a JSON value so that
+// semantically equal documents always produce byte-identical output,
+// regardless of source key order, numeric formatting, or whitespace.
+// This makes it suitable as the input to content hashes and detached
+// signatures that must survive re-marshaling by intermediaries.
+package canonical
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// MarshalCanonical serializes v as JCS-canonical JSON: object keys are
+// sorted lexicographically by UTF-16 code unit, numbers are rendered
+// using the ECMAScript Number::toString algorithm (the shortest
+// round-trippable decimal form), strings carry only the escapes
+// RFC 8259 §7 requires, and NaN/±Inf are rejected.
+func MarshalCanonical(v any) ([]byte, error) {
+	// Route v through encoding/json first so that struct tags, custom
+	// MarshalJSON implementations, and standard Go type conventions are
+	// honored exactly as they would be for any other JSON output in
+	// this codebase; only the re-serialization below is JCS-specific.
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("canonical: marshal: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var parsed any
+	if err := dec.Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("canonical: decode: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := encodeValue(&buf, parsed); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeValue(buf *bytes.Buffer, v any) error {
+	switch val := v.(type) {
+	case nil:
+		buf.WriteString("null")
+		return nil
+	case bool:
+		if val {
+			buf.WriteString("true")
+		} else {
+			buf.WriteString("false")
+		}
+		return nil
+	case json.Number:
+		return encodeNumber(buf, val)
+	case string:
+		encodeString(buf, val)
+		return nil
+	case []any:
+		return encodeArray(buf, val)
+	case map[string]any:
+		return encodeObject(buf, val)
+	default:
+		return fmt.Errorf("canonical: unsupported decoded type %T", v)
+	}
+}
+
+func encodeArray(buf *bytes.Buffer, arr []any) error {
+	buf.WriteByte('[')
+	for i, item := range arr {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encodeValue(buf, item); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+func encodeObject(buf *bytes.Buffer, obj map[string]any) error {
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return lessUTF16(keys[i], keys[j]) })
+
+	buf.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodeString(buf, k)
+		buf.WriteByte(':')
+		if err := encodeValue(buf, obj[k]); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// lessUTF16 reports whether a sorts before b when compared by UTF-16
+// code unit, as RFC 8785 §3.2.3 requires. This differs from a raw UTF-8
+// byte comparison for characters outside the Basic Multilingual Plane,
+// which UTF-16 represents as surrogate pairs in the U+D800-U+DFFF range.
+func lessUTF16(a, b string) bool {
+	au := utf16.Encode([]rune(a))
+	bu := utf16.Encode([]rune(b))
+	for i := 0; i < len(au) && i < len(bu); i++ {
+		if au[i] != bu[i] {
+			return au[i] < bu[i]
+		}
+	}
+	return len(au) < len(bu)
+}
+
+// encodeString writes s as a JSON string literal using only the escapes
+// RFC 8259 §7 mandates: quote, backslash, and the C0 control characters.
+// Every other character, including non-ASCII ones, is copied verbatim.
+func encodeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// encodeNumber renders n using the ECMAScript Number::toString algorithm
+// (ECMA-262 §6.1.6.1.20): the shortest decimal digit string that round
+// trips to the same IEEE-754 double, laid out as a plain integer,
+// fixed-point, or exponential form depending on its magnitude.
+func encodeNumber(buf *bytes.Buffer, n json.Number) error {
+	f, err := n.Float64()
+	if err != nil {
+		return fmt.Errorf("canonical: invalid number %q: %w", n, err)
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Errorf("canonical: cannot encode non-finite number %v", f)
+	}
+
+	s, err := formatESNumber(f)
+	if err != nil {
+		return err
+	}
+	buf.WriteString(s)
+	return nil
+}
+
+func formatESNumber(f float64) (string, error) {
+	if f == 0 {
+		return "0", nil
+	}
+
+	neg := math.Signbit(f)
+	if neg {
+		f = -f
+	}
+
+	// strconv's shortest round-trippable exponential form gives us the
+	// same digit string and decimal exponent the ES algorithm starts
+	// from; only the layout rules below are JCS/ES-specific.
+	exp := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissaPart, expPart, ok := strings.Cut(exp, "e")
+	if !ok {
+		return "", fmt.Errorf("canonical: unexpected float format %q", exp)
+	}
+	e, err := strconv.Atoi(expPart)
+	if err != nil {
+		return "", fmt.Errorf("canonical: unexpected exponent %q: %w", expPart, err)
+	}
+
+	digits := strings.Replace(mantissaPart, ".", "", 1)
+	k := len(digits)
+	n := e + 1 // digits * 10^(n-k) == f, per ECMA-262
+
+	var out string
+	switch {
+	case k <= n && n <= 21:
+		out = digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		out = digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		out = "0." + strings.Repeat("0", -n) + digits
+	default:
+		mant := digits[:1]
+		if k > 1 {
+			mant += "." + digits[1:]
+		}
+		expVal := n - 1
+		sign := "+"
+		if expVal < 0 {
+			sign = "-"
+			expVal = -expVal
+		}
+		out = mant + "e" + sign + strconv.Itoa(expVal)
+	}
+
+	if neg {
+		out = "-" + out
+	}
+	return out, nil
+}
@@ -0,0 +1,105 @@
+package canonical
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalCanonical_SortsKeysLexicographically(t *testing.T) {
+	v := map[string]any{"b": 1, "a": 2, "c": 3}
+
+	data, err := MarshalCanonical(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":2,"b":1,"c":3}`, string(data))
+}
+
+func TestMarshalCanonical_NestedObjectsAndArrays(t *testing.T) {
+	v := map[string]any{
+		"z": []any{3, 1, map[string]any{"y": 1, "x": 2}},
+		"a": "hi",
+	}
+
+	data, err := MarshalCanonical(v)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":"hi","z":[3,1,{"x":2,"y":1}]}`, string(data))
+}
+
+func TestMarshalCanonical_IsStableAcrossKeyOrder(t *testing.T) {
+	a, err := MarshalCanonical(map[string]any{"a": 1, "b": 2})
+	require.NoError(t, err)
+	b, err := MarshalCanonical(map[string]any{"b": 2, "a": 1})
+	require.NoError(t, err)
+	assert.Equal(t, string(a), string(b))
+}
+
+func TestMarshalCanonical_NumberFormatting(t *testing.T) {
+	cases := map[string]string{
+		"int":      `1`,
+		"zero":     `0`,
+		"frac":     `1.5`,
+		"large":    `100000000000000000000`,
+		"small":    `0.000001`,
+		"tinyExp":  `1e-7`,
+		"negative": `-42.5`,
+		"bigExp":   `1e+21`,
+	}
+
+	inputs := map[string]float64{
+		"int":      1,
+		"zero":     0,
+		"frac":     1.5,
+		"large":    1e20,
+		"small":    0.000001,
+		"tinyExp":  0.0000001,
+		"negative": -42.5,
+		"bigExp":   1e21,
+	}
+
+	for name, f := range inputs {
+		v := map[string]any{"n": f}
+		data, err := MarshalCanonical(v)
+		require.NoError(t, err, name)
+		assert.Equal(t, `{"n":`+cases[name]+`}`, string(data), name)
+	}
+}
+
+func TestMarshalCanonical_RejectsNonFiniteNumbers(t *testing.T) {
+	_, err := MarshalCanonical(map[string]any{"n": math.NaN()})
+	assert.Error(t, err)
+
+	_, err = MarshalCanonical(map[string]any{"n": math.Inf(1)})
+	assert.Error(t, err)
+}
+
+func TestMarshalCanonical_StringEscaping(t *testing.T) {
+	v := map[string]any{"s": "line\nbreak\t\"quoted\"\\slash/ and unicode: \u00e9\u4e2d"}
+
+	data, err := MarshalCanonical(v)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"s\":\"line\\nbreak\\t\\\"quoted\\\"\\\\slash/ and unicode: \u00e9\u4e2d\"}", string(data))
+}
+
+func TestMarshalCanonical_SortsByUTF16CodeUnit(t *testing.T) {
+	// U+10000 is encoded in UTF-16 as the surrogate pair 0xD800,0xDC00,
+	// so it sorts before U+FFFF under UTF-16 code unit comparison even
+	// though U+10000 is the larger Unicode code point.
+	v := map[string]any{"\U00010000": 1, "\uffff": 2}
+
+	data, err := MarshalCanonical(v)
+	require.NoError(t, err)
+	assert.Equal(t, "{\"\U00010000\":1,\"\uffff\":2}", string(data))
+}
+
+func TestMarshalCanonical_StructWithTags(t *testing.T) {
+	type inner struct {
+		B int `json:"b"`
+		A int `json:"a"`
+	}
+
+	data, err := MarshalCanonical(inner{B: 1, A: 2})
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":2,"b":1}`, string(data))
+}
@@ -0,0 +1,69 @@
+package events
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivitySnapshotEvent_ContentIDStableAcrossReencoding(t *testing.T) {
+	event := NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"b": 1, "a": 2})
+
+	id, err := event.ContentID()
+	require.NoError(t, err)
+
+	data, err := event.ToJSON()
+	require.NoError(t, err)
+
+	var reencoded ActivitySnapshotEvent
+	require.NoError(t, json.Unmarshal(data, &reencoded))
+	reencoded.BaseEvent = event.BaseEvent
+
+	reencodedID, err := reencoded.ContentID()
+	require.NoError(t, err)
+	assert.Equal(t, id, reencodedID)
+}
+
+func TestActivitySnapshotEvent_ContentIDChangesWithContent(t *testing.T) {
+	a := NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "draft"})
+	b := NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "done"})
+
+	idA, err := a.ContentID()
+	require.NoError(t, err)
+	idB, err := b.ContentID()
+	require.NoError(t, err)
+	assert.NotEqual(t, idA, idB)
+}
+
+func TestActivitySnapshotEvent_SignAndVerify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	event := NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "draft"})
+
+	sig, err := event.Sign(priv)
+	require.NoError(t, err)
+
+	ok, err := event.Verify(pub, sig)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	event.Content = map[string]any{"status": "tampered"}
+	ok, err = event.Verify(pub, sig)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestMessage_CanonicalHashIgnoresMapKeyOrder(t *testing.T) {
+	a := Message{ID: "activity-1", Role: RoleActivity, ActivityType: "PLAN", ActivityContent: map[string]any{"a": 1, "b": 2}}
+	b := Message{ID: "activity-1", Role: RoleActivity, ActivityType: "PLAN", ActivityContent: map[string]any{"b": 2, "a": 1}}
+
+	hashA, err := a.CanonicalHash()
+	require.NoError(t, err)
+	hashB, err := b.CanonicalHash()
+	require.NoError(t, err)
+	assert.Equal(t, hashA, hashB)
+}
@@ -0,0 +1,292 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events/canonical"
+)
+
+// DiffActivity computes a minimal RFC 6902 JSON Patch that transforms
+// prev into next, so that ApplyPatch(prev, DiffActivity(prev, next))
+// reproduces next. Relocated subtrees (an object value moved to a
+// different key, or an array element moved to a different index) are
+// detected by comparing canonical-form hashes and collapsed into a
+// single "move" op instead of a remove/add pair; a subtree that is
+// duplicated rather than relocated is reported as "copy". If the
+// collapsed patch does not reproduce next (a move or copy source was
+// shifted by an earlier op in the same patch), the uncollapsed
+// add/remove/replace ops are returned instead.
+func DiffActivity(prev, next any) ([]JSONPatchOperation, error) {
+	prevG, err := toGeneric(prev)
+	if err != nil {
+		return nil, fmt.Errorf("DiffActivity: %w", err)
+	}
+	nextG, err := toGeneric(next)
+	if err != nil {
+		return nil, fmt.Errorf("DiffActivity: %w", err)
+	}
+
+	d := &differ{}
+	d.diffValue("", prevG, nextG)
+	patch := d.collapse()
+	if got, err := ApplyPatch(prevG, patch); err != nil || canonicalKey(got) != canonicalKey(nextG) {
+		patch = make([]JSONPatchOperation, 0, len(d.ops))
+		for _, op := range d.ops {
+			if op.op == "remove" {
+				patch = append(patch, JSONPatchOperation{Op: "remove", Path: op.path})
+			} else {
+				patch = append(patch, JSONPatchOperation{Op: op.op, Path: op.path, Value: op.value})
+			}
+		}
+	}
+	return patch, nil
+}
+
+// rawOp is an intermediate patch operation produced during tree walking,
+// before move/copy collapsing. For "remove" it additionally carries the
+// removed value so it can be matched against a later "add" by hash.
+type rawOp struct {
+	op    string
+	path  string
+	value any
+	// movePath is the path to use as a "move" operation's From when this
+	// remove is collapsed (see collapse). It differs from path for array
+	// removes: path is expressed against the array mid-walk, after the
+	// cursor has already accounted for inserts and kept elements that
+	// precede it, whereas movePath is the element's original index. That
+	// index is the right move source only when no earlier op in the patch
+	// has shifted the array; DiffActivity checks the collapsed patch and
+	// falls back to the uncollapsed ops when it is not.
+	movePath string
+}
+
+// stableEntry records a subtree that is present, unchanged, at the same
+// or a different path in both prev and next; it is a candidate "from"
+// source for a "copy" operation.
+type stableEntry struct {
+	path string
+	hash string
+}
+
+type differ struct {
+	ops    []rawOp
+	stable []stableEntry
+}
+
+func canonicalKey(v any) string {
+	data, err := canonical.MarshalCanonical(v)
+	if err != nil {
+		// Generic documents decoded from JSON are always
+		// canonicalizable; this only guards against future misuse.
+		return fmt.Sprintf("!uncanonicalizable:%#v", v)
+	}
+	return string(data)
+}
+
+func (d *differ) markStable(path string, v any) {
+	d.stable = append(d.stable, stableEntry{path: path, hash: canonicalKey(v)})
+}
+
+func (d *differ) diffValue(path string, prev, next any) {
+	prevHash, nextHash := canonicalKey(prev), canonicalKey(next)
+	if prevHash == nextHash {
+		d.markStable(path, next)
+		return
+	}
+
+	prevMap, prevIsMap := prev.(map[string]any)
+	nextMap, nextIsMap := next.(map[string]any)
+	if prevIsMap && nextIsMap {
+		d.diffObject(path, prevMap, nextMap)
+		return
+	}
+
+	prevArr, prevIsArr := prev.([]any)
+	nextArr, nextIsArr := next.([]any)
+	if prevIsArr && nextIsArr {
+		d.diffArray(path, prevArr, nextArr)
+		return
+	}
+
+	d.ops = append(d.ops, rawOp{op: "replace", path: path, value: next})
+}
+
+func (d *differ) diffObject(path string, prev, next map[string]any) {
+	keys := unionSortedKeys(prev, next)
+	for _, k := range keys {
+		childPath := joinPointer(path, k)
+		pv, pOk := prev[k]
+		nv, nOk := next[k]
+
+		switch {
+		case pOk && !nOk:
+			d.ops = append(d.ops, rawOp{op: "remove", path: childPath, value: pv, movePath: childPath})
+		case !pOk && nOk:
+			d.ops = append(d.ops, rawOp{op: "add", path: childPath, value: nv})
+		default:
+			d.diffValue(childPath, pv, nv)
+		}
+	}
+}
+
+// diffArray aligns prev and next with a longest-common-subsequence over
+// element hashes, then walks the resulting edit script to emit remove/add
+// ops at the index each element occupies in the array as it is mutated
+// in place (a delete leaves the cursor in place since the following
+// element shifts into it; an insert or kept element advances it).
+func (d *differ) diffArray(path string, prev, next []any) {
+	n, m := len(prev), len(next)
+	prevHashes := make([]string, n)
+	for i, v := range prev {
+		prevHashes[i] = canonicalKey(v)
+	}
+	nextHashes := make([]string, m)
+	for j, v := range next {
+		nextHashes[j] = canonicalKey(v)
+	}
+
+	dp := make([][]int, n+1)
+	for i := range dp {
+		dp[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if prevHashes[i] == nextHashes[j] {
+				dp[i][j] = dp[i+1][j+1] + 1
+			} else if dp[i+1][j] >= dp[i][j+1] {
+				dp[i][j] = dp[i+1][j]
+			} else {
+				dp[i][j] = dp[i][j+1]
+			}
+		}
+	}
+
+	i, j, cursor := 0, 0, 0
+	for i < n && j < m {
+		switch {
+		case prevHashes[i] == nextHashes[j]:
+			d.markStable(joinPointer(path, strconv.Itoa(cursor)), next[j])
+			i++
+			j++
+			cursor++
+		case dp[i+1][j] >= dp[i][j+1]:
+			d.ops = append(d.ops, rawOp{
+				op: "remove", path: joinPointer(path, strconv.Itoa(cursor)),
+				value: prev[i], movePath: joinPointer(path, strconv.Itoa(i)),
+			})
+			i++
+		default:
+			d.ops = append(d.ops, rawOp{op: "add", path: joinPointer(path, strconv.Itoa(cursor)), value: next[j]})
+			j++
+			cursor++
+		}
+	}
+	for ; i < n; i++ {
+		d.ops = append(d.ops, rawOp{
+			op: "remove", path: joinPointer(path, strconv.Itoa(cursor)),
+			value: prev[i], movePath: joinPointer(path, strconv.Itoa(i)),
+		})
+	}
+	for ; j < m; j++ {
+		d.ops = append(d.ops, rawOp{op: "add", path: joinPointer(path, strconv.Itoa(cursor)), value: next[j]})
+		cursor++
+	}
+}
+
+// collapse turns the raw add/remove/replace ops into the final patch,
+// merging a remove/add pair with matching content hashes into a single
+// "move", and rewriting an add whose content hash matches an untouched
+// part of the document into a "copy".
+func (d *differ) collapse() []JSONPatchOperation {
+	removeByHash := make(map[string]int)
+	for i, op := range d.ops {
+		if op.op != "remove" {
+			continue
+		}
+		h := canonicalKey(op.value)
+		if _, exists := removeByHash[h]; !exists {
+			removeByHash[h] = i
+		}
+	}
+
+	stableByHash := make(map[string]string)
+	for _, s := range d.stable {
+		if _, exists := stableByHash[s.hash]; !exists {
+			stableByHash[s.hash] = s.path
+		}
+	}
+
+	// First pass: decide, for every add, whether it pairs with a remove
+	// (becomes a "move") or an untouched subtree (becomes a "copy").
+	// This must run to completion before building the output so that a
+	// remove appearing before its matching add in d.ops (e.g. an object
+	// key removed earlier, alphabetically, than the key it moved to)
+	// is still recognized as consumed.
+	consumedRemove := make(map[int]bool)
+	addFrom := make(map[int]string) // op index -> "move" source path
+	addCopy := make(map[int]string) // op index -> "copy" source path
+	for i, op := range d.ops {
+		if op.op != "add" {
+			continue
+		}
+		h := canonicalKey(op.value)
+		if srcIdx, ok := removeByHash[h]; ok && !consumedRemove[srcIdx] {
+			consumedRemove[srcIdx] = true
+			addFrom[i] = d.ops[srcIdx].movePath
+			continue
+		}
+		if from, ok := stableByHash[h]; ok && from != op.path {
+			addCopy[i] = from
+		}
+	}
+
+	finalOps := make([]JSONPatchOperation, 0, len(d.ops))
+	for i, op := range d.ops {
+		switch op.op {
+		case "remove":
+			if consumedRemove[i] {
+				continue
+			}
+			finalOps = append(finalOps, JSONPatchOperation{Op: "remove", Path: op.path})
+		case "replace":
+			finalOps = append(finalOps, JSONPatchOperation{Op: "replace", Path: op.path, Value: op.value})
+		case "add":
+			if from, ok := addFrom[i]; ok {
+				finalOps = append(finalOps, JSONPatchOperation{Op: "move", From: from, Path: op.path})
+			} else if from, ok := addCopy[i]; ok {
+				finalOps = append(finalOps, JSONPatchOperation{Op: "copy", From: from, Path: op.path})
+			} else {
+				finalOps = append(finalOps, JSONPatchOperation{Op: "add", Path: op.path, Value: op.value})
+			}
+		}
+	}
+	return finalOps
+}
+
+func unionSortedKeys(a, b map[string]any) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	for k := range b {
+		if !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	sortStrings(keys)
+	return keys
+}
+
+func sortStrings(s []string) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && s[j-1] > s[j]; j-- {
+			s[j-1], s[j] = s[j], s[j-1]
+		}
+	}
+}
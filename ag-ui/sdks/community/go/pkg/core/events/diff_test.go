@@ -0,0 +1,241 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func applyAndCheck(t *testing.T, prev, next any) {
+	t.Helper()
+	patch, err := DiffActivity(prev, next)
+	require.NoError(t, err)
+
+	for i, op := range patch {
+		require.NoError(t, validateJSONPatchOperation(op), "op %d", i)
+	}
+
+	got, err := ApplyPatch(prev, patch)
+	require.NoError(t, err)
+
+	nextG, err := toGeneric(next)
+	require.NoError(t, err)
+	assert.Equal(t, nextG, got)
+}
+
+func TestDiffActivity_AddRemoveReplaceFields(t *testing.T) {
+	prev := map[string]any{"status": "draft", "owner": "alice"}
+	next := map[string]any{"status": "done", "reviewer": "bob"}
+
+	patch, err := DiffActivity(prev, next)
+	require.NoError(t, err)
+	assert.NotEmpty(t, patch)
+
+	applyAndCheck(t, prev, next)
+}
+
+func TestDiffActivity_NoChangeYieldsEmptyPatch(t *testing.T) {
+	doc := map[string]any{"status": "draft"}
+	patch, err := DiffActivity(doc, doc)
+	require.NoError(t, err)
+	assert.Empty(t, patch)
+}
+
+func TestDiffActivity_NestedObjectChange(t *testing.T) {
+	prev := map[string]any{"meta": map[string]any{"status": "draft", "version": float64(1)}}
+	next := map[string]any{"meta": map[string]any{"status": "done", "version": float64(1)}}
+
+	patch, err := DiffActivity(prev, next)
+	require.NoError(t, err)
+	require.Len(t, patch, 1)
+	assert.Equal(t, "replace", patch[0].Op)
+	assert.Equal(t, "/meta/status", patch[0].Path)
+
+	applyAndCheck(t, prev, next)
+}
+
+func TestDiffActivity_ArrayReorderDetectedAsMove(t *testing.T) {
+	prev := map[string]any{"items": []any{"a", "b", "c"}}
+	next := map[string]any{"items": []any{"c", "a", "b"}}
+
+	patch, err := DiffActivity(prev, next)
+	require.NoError(t, err)
+
+	var moves int
+	for _, op := range patch {
+		if op.Op == "move" {
+			moves++
+		}
+	}
+	assert.Equal(t, 1, moves)
+
+	applyAndCheck(t, prev, next)
+}
+
+func TestDiffActivity_ObjectKeyRenameDetectedAsMove(t *testing.T) {
+	prev := map[string]any{"draftStatus": "in-progress"}
+	next := map[string]any{"finalStatus": "in-progress"}
+
+	patch, err := DiffActivity(prev, next)
+	require.NoError(t, err)
+	require.Len(t, patch, 1)
+	assert.Equal(t, "move", patch[0].Op)
+	assert.Equal(t, "/draftStatus", patch[0].From)
+	assert.Equal(t, "/finalStatus", patch[0].Path)
+
+	applyAndCheck(t, prev, next)
+}
+
+func TestDiffActivity_DuplicatedSubtreeDetectedAsCopy(t *testing.T) {
+	prev := map[string]any{"primary": map[string]any{"status": "draft"}}
+	next := map[string]any{
+		"primary": map[string]any{"status": "draft"},
+		"backup":  map[string]any{"status": "draft"},
+	}
+
+	patch, err := DiffActivity(prev, next)
+	require.NoError(t, err)
+	require.Len(t, patch, 1)
+	assert.Equal(t, "copy", patch[0].Op)
+	assert.Equal(t, "/primary", patch[0].From)
+	assert.Equal(t, "/backup", patch[0].Path)
+
+	applyAndCheck(t, prev, next)
+}
+
+func TestDiffActivity_ArrayAppendAndRemove(t *testing.T) {
+	prev := map[string]any{"items": []any{"a", "b"}}
+	next := map[string]any{"items": []any{"a", "b", "c"}}
+	applyAndCheck(t, prev, next)
+
+	prev2 := map[string]any{"items": []any{"a", "b", "c"}}
+	next2 := map[string]any{"items": []any{"a", "c"}}
+	applyAndCheck(t, prev2, next2)
+}
+
+func TestDiffActivity_RootTypeChangeAppliesCleanly(t *testing.T) {
+	applyAndCheck(t, map[string]any{"status": "draft"}, []any{"status", "draft"})
+	applyAndCheck(t, map[string]any{"status": "draft"}, "draft")
+	applyAndCheck(t, "draft", map[string]any{"status": "draft"})
+}
+
+func TestApplyPatch_AllSixOperations(t *testing.T) {
+	doc := map[string]any{
+		"a": "1",
+		"b": map[string]any{"x": "keep"},
+		"c": []any{"one", "two"},
+	}
+
+	patch := []JSONPatchOperation{
+		{Op: "add", Path: "/d", Value: "new"},
+		{Op: "remove", Path: "/a"},
+		{Op: "replace", Path: "/b/x", Value: "changed"},
+		{Op: "move", From: "/c/0", Path: "/c/1"},
+		{Op: "copy", From: "/d", Path: "/e"},
+		{Op: "test", Path: "/e", Value: "new"},
+	}
+
+	got, err := ApplyPatch(doc, patch)
+	require.NoError(t, err)
+
+	result, ok := got.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "new", result["d"])
+	assert.Equal(t, "new", result["e"])
+	assert.Nil(t, result["a"])
+	assert.Equal(t, "changed", result["b"].(map[string]any)["x"])
+	assert.Equal(t, []any{"two", "one"}, result["c"])
+}
+
+func TestApplyPatch_TestOperationFailureAborts(t *testing.T) {
+	doc := map[string]any{"status": "draft"}
+	patch := []JSONPatchOperation{{Op: "test", Path: "/status", Value: "done"}}
+
+	_, err := ApplyPatch(doc, patch)
+	assert.Error(t, err)
+}
+
+func TestApplyPatch_InvalidOperationRejected(t *testing.T) {
+	doc := map[string]any{"status": "draft"}
+	patch := []JSONPatchOperation{{Op: "move", Path: "/status"}}
+
+	_, err := ApplyPatch(doc, patch)
+	assert.Error(t, err)
+}
+
+func TestValidateJSONPatchOperation_RemoveRejectsValueAndFrom(t *testing.T) {
+	assert.Error(t, validateJSONPatchOperation(JSONPatchOperation{Op: "remove", Path: "/a", Value: "x"}))
+	assert.Error(t, validateJSONPatchOperation(JSONPatchOperation{Op: "remove", Path: "/a", From: "/b"}))
+	assert.NoError(t, validateJSONPatchOperation(JSONPatchOperation{Op: "remove", Path: "/a"}))
+}
+
+func TestValidateJSONPatchOperation_MoveCopyAllowRootFrom(t *testing.T) {
+	// From "" is the RFC 6901 root pointer, a legal source for move/copy
+	// (e.g. "copy" with From "" duplicates the whole document at Path).
+	assert.NoError(t, validateJSONPatchOperation(JSONPatchOperation{Op: "move", Path: "/a"}))
+	assert.NoError(t, validateJSONPatchOperation(JSONPatchOperation{Op: "copy", Path: "/a"}))
+	assert.NoError(t, validateJSONPatchOperation(JSONPatchOperation{Op: "move", Path: "/a", From: "/b"}))
+}
+
+func TestApplyPatch_CopyFromRootDuplicatesWholeDocument(t *testing.T) {
+	doc := map[string]any{"status": "draft"}
+	patch := []JSONPatchOperation{{Op: "copy", Path: "/snapshot", From: ""}}
+
+	got, err := ApplyPatch(doc, patch)
+	require.NoError(t, err)
+	result := got.(map[string]any)
+	assert.Equal(t, map[string]any{"status": "draft"}, result["snapshot"])
+}
+
+func TestValidateJSONPatchOperation_TestRequiresValue(t *testing.T) {
+	assert.Error(t, validateJSONPatchOperation(JSONPatchOperation{Op: "test", Path: "/a"}))
+	assert.NoError(t, validateJSONPatchOperation(JSONPatchOperation{Op: "test", Path: "/a", Value: "x"}))
+}
+
+func TestActivityStream_FirstSetYieldsSnapshot(t *testing.T) {
+	stream := NewActivityStream(DefaultActivityStreamConfig())
+
+	event, err := stream.Set("activity-1", "PLAN", map[string]any{"status": "draft"})
+	require.NoError(t, err)
+	snapshot, ok := event.(*ActivitySnapshotEvent)
+	require.True(t, ok)
+	assert.Equal(t, "activity-1", snapshot.MessageID)
+}
+
+func TestActivityStream_SubsequentSetYieldsDelta(t *testing.T) {
+	stream := NewActivityStream(DefaultActivityStreamConfig())
+
+	_, err := stream.Set("activity-1", "PLAN", map[string]any{"status": "draft", "owner": "alice", "priority": "low"})
+	require.NoError(t, err)
+
+	event, err := stream.Set("activity-1", "PLAN", map[string]any{"status": "done", "owner": "alice", "priority": "low"})
+	require.NoError(t, err)
+	delta, ok := event.(*ActivityDeltaEvent)
+	require.True(t, ok)
+	assert.Equal(t, "activity-1", delta.MessageID)
+}
+
+func TestActivityStream_UnchangedContentYieldsNoEvent(t *testing.T) {
+	stream := NewActivityStream(DefaultActivityStreamConfig())
+	content := map[string]any{"status": "draft"}
+
+	_, err := stream.Set("activity-1", "PLAN", content)
+	require.NoError(t, err)
+
+	event, err := stream.Set("activity-1", "PLAN", content)
+	require.NoError(t, err)
+	assert.Nil(t, event)
+}
+
+func TestActivityStream_LargePatchFallsBackToSnapshot(t *testing.T) {
+	stream := NewActivityStream(ActivityStreamConfig{MaxPatchOps: 1})
+
+	_, err := stream.Set("activity-1", "PLAN", map[string]any{"a": "1", "b": "2"})
+	require.NoError(t, err)
+
+	event, err := stream.Set("activity-1", "PLAN", map[string]any{"a": "x", "b": "y"})
+	require.NoError(t, err)
+	_, ok := event.(*ActivitySnapshotEvent)
+	assert.True(t, ok)
+}
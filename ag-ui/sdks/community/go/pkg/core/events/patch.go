@@ -0,0 +1,45 @@
+package events
+
+import "fmt"
+
+// JSONPatchOperation is a single RFC 6902 JSON Patch operation.
+type JSONPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// validateJSONPatchOperation checks that op is a well-formed RFC 6902
+// operation: its Op is one of the six defined kinds, test carries a
+// Value, and remove carries no Value (nor a meaningful From - see
+// below). Neither Path nor From is checked for emptiness: "" is the RFC
+// 6901 pointer to the whole document and is a legal value of a required
+// field, not a sign the field is missing (e.g. a "copy" with From ""
+// duplicates the whole document under Path); operations for which the
+// root is not a sensible target (e.g. "remove") reject it themselves
+// when applied.
+func validateJSONPatchOperation(op JSONPatchOperation) error {
+	switch op.Op {
+	case "add", "replace":
+		// Value may legitimately be the zero value (e.g. false, 0, ""),
+		// so its presence isn't checked.
+	case "remove":
+		if op.From != "" {
+			return fmt.Errorf("remove operation must not set from")
+		}
+		if op.Value != nil {
+			return fmt.Errorf("remove operation must not set value")
+		}
+	case "move", "copy":
+		// From "" is the document root, a legal source; see doc comment.
+	case "test":
+		if op.Value == nil {
+			return fmt.Errorf("test operation requires value")
+		}
+	default:
+		return fmt.Errorf("unsupported op %q", op.Op)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// escapePointerToken escapes a single JSON Pointer (RFC 6901) reference
+// token: '~' becomes "~0" and '/' becomes "~1". The order matters,
+// since escaping '/' first would corrupt the "~0" produced for '~'.
+func escapePointerToken(token string) string {
+	token = strings.ReplaceAll(token, "~", "~0")
+	token = strings.ReplaceAll(token, "/", "~1")
+	return token
+}
+
+// unescapePointerToken reverses escapePointerToken.
+func unescapePointerToken(token string) string {
+	token = strings.ReplaceAll(token, "~1", "/")
+	token = strings.ReplaceAll(token, "~0", "~")
+	return token
+}
+
+// splitPointer breaks a JSON Pointer into its unescaped reference
+// tokens. The root pointer "" yields no tokens.
+func splitPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must start with /", pointer)
+	}
+
+	raw := strings.Split(pointer[1:], "/")
+	tokens := make([]string, len(raw))
+	for i, t := range raw {
+		tokens[i] = unescapePointerToken(t)
+	}
+	return tokens, nil
+}
+
+// joinPointer builds a JSON Pointer from a parent pointer and a child
+// reference token, escaping the token as RFC 6901 requires.
+func joinPointer(parent, token string) string {
+	return parent + "/" + escapePointerToken(token)
+}
+
+// navigateParent walks doc to the parent container of the location
+// named by pointer, returning that container and the final reference
+// token so callers can read, set, or delete the target location.
+func navigateParent(doc any, pointer string) (container any, token string, err error) {
+	tokens, err := splitPointer(pointer)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(tokens) == 0 {
+		return nil, "", fmt.Errorf("pointer %q has no parent", pointer)
+	}
+
+	cur := doc
+	for _, t := range tokens[:len(tokens)-1] {
+		next, err := step(cur, t)
+		if err != nil {
+			return nil, "", err
+		}
+		cur = next
+	}
+	return cur, tokens[len(tokens)-1], nil
+}
+
+// navigate resolves pointer against doc and returns the value found
+// there.
+func navigate(doc any, pointer string) (any, error) {
+	tokens, err := splitPointer(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := doc
+	for _, t := range tokens {
+		next, err := step(cur, t)
+		if err != nil {
+			return nil, err
+		}
+		cur = next
+	}
+	return cur, nil
+}
+
+func step(cur any, token string) (any, error) {
+	switch v := cur.(type) {
+	case map[string]any:
+		child, ok := v[token]
+		if !ok {
+			return nil, fmt.Errorf("path segment %q not found", token)
+		}
+		return child, nil
+	case []any:
+		idx, err := strconv.Atoi(token)
+		if err != nil || idx < 0 || idx >= len(v) {
+			return nil, fmt.Errorf("array index %q out of range", token)
+		}
+		return v[idx], nil
+	default:
+		return nil, fmt.Errorf("cannot descend into %T at %q", cur, token)
+	}
+}
@@ -0,0 +1,42 @@
+package transport
+
+// ringBuffer retains the most recent published events so a reconnecting
+// subscriber can replay from its last-seen sequence number. It is not
+// safe for concurrent use; callers serialize access (EventBus does so
+// under its own mutex).
+type ringBuffer struct {
+	entries []Sequenced // oldest first
+	cap     int
+}
+
+func newRingBuffer(capacity int) *ringBuffer {
+	if capacity < 0 {
+		capacity = 0
+	}
+	return &ringBuffer{cap: capacity}
+}
+
+func (r *ringBuffer) append(entry Sequenced) {
+	if r.cap == 0 {
+		return
+	}
+	r.entries = append(r.entries, entry)
+	if len(r.entries) > r.cap {
+		r.entries = r.entries[len(r.entries)-r.cap:]
+	}
+}
+
+// since returns every retained entry with Seq greater than afterSeq,
+// oldest first. If afterSeq is older than everything still retained,
+// the caller has fallen too far behind to replay gaplessly; since
+// returns whatever remains rather than erroring, leaving the decision
+// of how to treat a gap to the caller.
+func (r *ringBuffer) since(afterSeq uint64) []Sequenced {
+	out := make([]Sequenced, 0, len(r.entries))
+	for _, e := range r.entries {
+		if e.Seq > afterSeq {
+			out = append(out, e)
+		}
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// defaultSubscriberQueueSize bounds how many events a slow subscriber
+// may queue before backpressure kicks in. Chosen generously enough that
+// normal reconnect/retry jitter doesn't trigger drops, while still
+// bounding per-subscriber memory.
+const defaultSubscriberQueueSize = 256
+
+// SSEHandler returns an http.Handler that streams events matching
+// filter as Server-Sent Events. Each event is written as
+//
+//	id: <seq>
+//	event: <type>
+//	data: <json>
+//
+// followed by a blank line. A client that reconnects with a
+// Last-Event-ID header resumes from that sequence number via
+// EventBus.SubscribeFrom instead of only seeing events published after
+// it reconnected.
+func (b *EventBus) SSEHandler(filter Filter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+
+		var afterSeq uint64
+		if lastID := r.Header.Get("Last-Event-ID"); lastID != "" {
+			if parsed, err := strconv.ParseUint(lastID, 10, 64); err == nil {
+				afterSeq = parsed
+			}
+		}
+
+		sub, backlog := b.SubscribeFrom(filter, defaultSubscriberQueueSize, afterSeq)
+		defer sub.Close()
+
+		for _, entry := range backlog {
+			if !writeSSEEvent(w, entry) {
+				return
+			}
+		}
+		flusher.Flush()
+
+		ctx := r.Context()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.Ready():
+				for {
+					entry, ok := sub.Next()
+					if !ok {
+						break
+					}
+					if !writeSSEEvent(w, entry) {
+						return
+					}
+				}
+				flusher.Flush()
+			}
+		}
+	})
+}
+
+func writeSSEEvent(w http.ResponseWriter, entry Sequenced) bool {
+	data, err := entry.Event.ToJSON()
+	if err != nil {
+		return true
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", entry.Seq, entry.Event.Type(), data)
+	return err == nil
+}
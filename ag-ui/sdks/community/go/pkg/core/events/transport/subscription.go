@@ -0,0 +1,155 @@
+package transport
+
+import (
+	"sync"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+)
+
+// coalesceThreshold is how full (as a fraction of queueSize) a
+// subscriber's queue must be before enqueue starts merging contiguous
+// deltas instead of appending them outright. Below this, events are
+// delivered one-for-one so a healthy subscriber sees every delta.
+const coalesceThreshold = 0.5
+
+// Subscription is a single consumer's view of an EventBus: an ordered
+// queue of events matching its Filter, fed by EventBus.Publish and
+// drained by Next. The queue is held to its capacity by dropping
+// snapshots; a queue holding only deltas grows past it (see
+// dropOldestSnapshot).
+type Subscription struct {
+	id     uint64
+	bus    *EventBus
+	filter Filter
+
+	mu       sync.Mutex
+	queue    []Sequenced
+	capacity int
+	notify   chan struct{}
+	closed   bool
+}
+
+func newSubscription(id uint64, filter Filter, queueSize int) *Subscription {
+	if queueSize <= 0 {
+		queueSize = 1
+	}
+	return &Subscription{
+		id:       id,
+		filter:   filter,
+		capacity: queueSize,
+		notify:   make(chan struct{}, 1),
+	}
+}
+
+// Filter returns the Filter this subscription was created with.
+func (s *Subscription) Filter() Filter {
+	return s.filter
+}
+
+// Ready is signaled (non-blocking, single-slot) whenever an event is
+// enqueued, so a consumer can select on it alongside other work.
+func (s *Subscription) Ready() <-chan struct{} {
+	return s.notify
+}
+
+// Next returns the oldest queued event, if any, removing it from the
+// queue.
+func (s *Subscription) Next() (Sequenced, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.queue) == 0 {
+		return Sequenced{}, false
+	}
+	entry := s.queue[0]
+	s.queue = s.queue[1:]
+	return entry, true
+}
+
+// Close unregisters the subscription from its bus. Safe to call more
+// than once.
+func (s *Subscription) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	if s.bus != nil {
+		s.bus.unsubscribe(s.id)
+	}
+}
+
+func (s *Subscription) enqueue(entry Sequenced) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	fallingBehind := float64(len(s.queue)) >= float64(s.capacity)*coalesceThreshold
+	if fallingBehind && s.coalesceInto(entry) {
+		s.signal()
+		return
+	}
+
+	if len(s.queue) >= s.capacity {
+		s.dropOldestSnapshot()
+	}
+	s.queue = append(s.queue, entry)
+	s.signal()
+}
+
+// coalesceInto merges entry into the tail of the queue when both are
+// ActivityDeltaEvents for the same activity: applying the merged
+// patch reproduces the same document as applying the two patches in
+// sequence, so nothing is lost by sending one event instead of two.
+func (s *Subscription) coalesceInto(entry Sequenced) bool {
+	if len(s.queue) == 0 {
+		return false
+	}
+
+	next, ok := entry.Event.(*events.ActivityDeltaEvent)
+	if !ok {
+		return false
+	}
+
+	tailIdx := len(s.queue) - 1
+	tail, ok := s.queue[tailIdx].Event.(*events.ActivityDeltaEvent)
+	if !ok || tail.MessageID != next.MessageID || tail.ActivityType != next.ActivityType {
+		return false
+	}
+
+	merged := events.NewActivityDeltaEvent(next.MessageID, next.ActivityType, append(append([]events.JSONPatchOperation{}, tail.Patch...), next.Patch...))
+	s.queue[tailIdx] = Sequenced{Seq: entry.Seq, Event: merged}
+	return true
+}
+
+// dropOldestSnapshot removes the oldest ActivitySnapshotEvent in the
+// queue to make room, per the backpressure policy of never dropping a
+// delta: a snapshot is a complete, self-contained replacement for
+// anything older, so discarding one loses nothing a later snapshot or
+// the live document wouldn't already cover, whereas a delta's patch is
+// not idempotent and silently dropping it would corrupt every
+// subscriber's reconstructed document from that point on. If the queue
+// holds no snapshot - every entry is an undelivered delta - this does
+// nothing and the caller lets the queue grow past capacity rather than
+// drop one.
+func (s *Subscription) dropOldestSnapshot() {
+	for i, entry := range s.queue {
+		if _, ok := entry.Event.(*events.ActivitySnapshotEvent); ok {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			return
+		}
+	}
+}
+
+func (s *Subscription) signal() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
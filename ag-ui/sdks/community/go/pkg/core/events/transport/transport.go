@@ -0,0 +1,182 @@
+// Package transport exposes AG-UI events to remote subscribers over two
+// wire adapters, Server-Sent Events and WebSocket, both built on the
+// same in-process EventBus. A producer calls Publish once per event; an
+// EventBus fans it out, in publish order, to every Subscription whose
+// Filter matches.
+package transport
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+)
+
+// Filter selects which events a Subscription receives. A zero Filter
+// matches everything. Types restricts to a set of event kinds;
+// MessageID and ActivityType further restrict to activity
+// snapshot/delta events for one activity.
+type Filter struct {
+	Types        []events.EventType
+	MessageID    string
+	ActivityType string
+}
+
+func (f Filter) matches(event events.Event) bool {
+	if len(f.Types) > 0 {
+		ok := false
+		for _, t := range f.Types {
+			if t == event.Type() {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if f.MessageID == "" && f.ActivityType == "" {
+		return true
+	}
+
+	messageID, activityType, ok := activityKey(event)
+	if !ok {
+		// A filter scoped to an activity never matches an event with
+		// no MessageID/ActivityType of its own.
+		return false
+	}
+	if f.MessageID != "" && f.MessageID != messageID {
+		return false
+	}
+	if f.ActivityType != "" && f.ActivityType != activityType {
+		return false
+	}
+	return true
+}
+
+func activityKey(event events.Event) (messageID, activityType string, ok bool) {
+	switch e := event.(type) {
+	case *events.ActivitySnapshotEvent:
+		return e.MessageID, e.ActivityType, true
+	case *events.ActivityDeltaEvent:
+		return e.MessageID, e.ActivityType, true
+	default:
+		return "", "", false
+	}
+}
+
+// Sequenced pairs an event with the sequence number the bus assigned it
+// at publish time. Sequence numbers are bus-local, monotonically
+// increasing, and are what SSE's Last-Event-ID and the WebSocket resume
+// token reference when a reconnecting subscriber asks to replay.
+type Sequenced struct {
+	Seq   uint64
+	Event events.Event
+}
+
+// EventBus fans out published events to subscribers, subject to each
+// subscriber's Filter, and retains a bounded replay buffer so a
+// reconnecting subscriber can resume from the last sequence number it
+// saw instead of missing events entirely.
+type EventBus struct {
+	seq atomic.Uint64
+
+	mu          sync.Mutex
+	subscribers map[uint64]*Subscription
+	nextSubID   uint64
+	ring        *ringBuffer
+}
+
+// NewEventBus creates an EventBus that retains the last replayBufferSize
+// published events for replay.
+func NewEventBus(replayBufferSize int) *EventBus {
+	return &EventBus{
+		subscribers: make(map[uint64]*Subscription),
+		ring:        newRingBuffer(replayBufferSize),
+	}
+}
+
+// Publish assigns the next sequence number to event and delivers it, in
+// that order, to every current subscriber whose Filter matches.
+func (b *EventBus) Publish(event events.Event) {
+	// Sequence assignment, the ring append and the enqueues all happen
+	// under b.mu so concurrent Publish calls reach the ring and every
+	// subscriber in sequence order.
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	seq := b.seq.Add(1)
+	entry := Sequenced{Seq: seq, Event: event}
+
+	b.ring.append(entry)
+	for _, sub := range b.subscribers {
+		if sub.filter.matches(event) {
+			sub.enqueue(entry)
+		}
+	}
+}
+
+// Subscribe registers a new Subscription matching filter. The caller
+// must call Subscription.Close when done to free its queue.
+func (b *EventBus) Subscribe(filter Filter, queueSize int) *Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.subscribeLocked(filter, queueSize)
+}
+
+func (b *EventBus) subscribeLocked(filter Filter, queueSize int) *Subscription {
+	b.nextSubID++
+	sub := newSubscription(b.nextSubID, filter, queueSize)
+	sub.bus = b
+	b.subscribers[sub.id] = sub
+	return sub
+}
+
+// SubscribeFrom registers a new Subscription matching filter and
+// computes its replay backlog (every retained event after afterSeq that
+// matches filter) as a single atomic step, so the two ranges it hands
+// the caller - the returned backlog and everything the Subscription
+// receives live from here on - are disjoint. Calling Subscribe and
+// ReplaySince separately leaves a window between them in which an event
+// published after Subscribe but before ReplaySince lands in both.
+func (b *EventBus) SubscribeFrom(filter Filter, queueSize int, afterSeq uint64) (*Subscription, []Sequenced) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := b.subscribeLocked(filter, queueSize)
+
+	all := b.ring.since(afterSeq)
+	backlog := make([]Sequenced, 0, len(all))
+	for _, entry := range all {
+		if filter.matches(entry.Event) {
+			backlog = append(backlog, entry)
+		}
+	}
+	return sub, backlog
+}
+
+// unsubscribe removes sub from the bus; called by Subscription.Close.
+func (b *EventBus) unsubscribe(id uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, id)
+}
+
+// ReplaySince returns every retained event with sequence number greater
+// than afterSeq that matches filter, oldest first. It is used to catch a
+// reconnecting subscriber up from its last-seen sequence number.
+func (b *EventBus) ReplaySince(afterSeq uint64, filter Filter) []Sequenced {
+	b.mu.Lock()
+	all := b.ring.since(afterSeq)
+	b.mu.Unlock()
+
+	out := make([]Sequenced, 0, len(all))
+	for _, entry := range all {
+		if filter.matches(entry.Event) {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
@@ -0,0 +1,211 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+)
+
+func waitReady(t *testing.T, sub *Subscription) {
+	t.Helper()
+	select {
+	case <-sub.Ready():
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for subscription to become ready")
+	}
+}
+
+func TestFilter_MatchesByType(t *testing.T) {
+	f := Filter{Types: []events.EventType{events.EventTypeActivitySnapshot}}
+	snapshot := events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"status": "draft"})
+	delta := events.NewActivityDeltaEvent("m1", "PLAN", nil)
+
+	assert.True(t, f.matches(snapshot))
+	assert.False(t, f.matches(delta))
+}
+
+func TestFilter_MatchesByActivity(t *testing.T) {
+	f := Filter{MessageID: "m1", ActivityType: "PLAN"}
+	match := events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{})
+	otherMessage := events.NewActivitySnapshotEvent("m2", "PLAN", map[string]any{})
+
+	assert.True(t, f.matches(match))
+	assert.False(t, f.matches(otherMessage))
+}
+
+func TestEventBus_PublishDeliversToMatchingSubscribers(t *testing.T) {
+	bus := NewEventBus(16)
+	sub := bus.Subscribe(Filter{}, 8)
+	defer sub.Close()
+
+	event := events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"status": "draft"})
+	bus.Publish(event)
+
+	waitReady(t, sub)
+	entry, ok := sub.Next()
+	require.True(t, ok)
+	assert.Equal(t, uint64(1), entry.Seq)
+	assert.Same(t, event, entry.Event)
+}
+
+func TestEventBus_ClosedSubscriptionReceivesNothing(t *testing.T) {
+	bus := NewEventBus(16)
+	sub := bus.Subscribe(Filter{}, 8)
+	sub.Close()
+
+	bus.Publish(events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{}))
+
+	_, ok := sub.Next()
+	assert.False(t, ok)
+}
+
+func TestSubscription_DropsOldestSnapshotNotDeltaWhenFull(t *testing.T) {
+	sub := newSubscription(1, Filter{}, 2)
+
+	sub.enqueue(Sequenced{Seq: 1, Event: events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"v": 1})})
+	sub.enqueue(Sequenced{Seq: 2, Event: events.NewActivityDeltaEvent("m1", "PLAN", nil)})
+	// Queue is at capacity; this enqueue should drop the snapshot from
+	// seq 1, keeping the delta from seq 2.
+	sub.enqueue(Sequenced{Seq: 3, Event: events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"v": 2})})
+
+	first, ok := sub.Next()
+	require.True(t, ok)
+	assert.Equal(t, uint64(2), first.Seq)
+
+	second, ok := sub.Next()
+	require.True(t, ok)
+	assert.Equal(t, uint64(3), second.Seq)
+}
+
+func TestSubscription_GrowsPastCapacityRatherThanDropAnUndeliveredDelta(t *testing.T) {
+	sub := newSubscription(1, Filter{}, 2)
+
+	// Distinct MessageIDs so coalesceInto never merges these together;
+	// the queue fills with nothing but deltas and no snapshot to drop.
+	sub.enqueue(Sequenced{Seq: 1, Event: events.NewActivityDeltaEvent("m1", "PLAN", nil)})
+	sub.enqueue(Sequenced{Seq: 2, Event: events.NewActivityDeltaEvent("m2", "PLAN", nil)})
+	// Queue is at capacity and holds no snapshot to drop; this delta
+	// must still be queued rather than silently lost.
+	sub.enqueue(Sequenced{Seq: 3, Event: events.NewActivityDeltaEvent("m3", "PLAN", nil)})
+
+	var seqs []uint64
+	for {
+		entry, ok := sub.Next()
+		if !ok {
+			break
+		}
+		seqs = append(seqs, entry.Seq)
+	}
+	assert.Equal(t, []uint64{1, 2, 3}, seqs)
+}
+
+func TestSubscription_CoalescesContiguousDeltasWhenFallingBehind(t *testing.T) {
+	sub := newSubscription(1, Filter{}, 2)
+
+	patchA := []events.JSONPatchOperation{{Op: "replace", Path: "/status", Value: "a"}}
+	patchB := []events.JSONPatchOperation{{Op: "replace", Path: "/owner", Value: "b"}}
+
+	// First enqueue crosses the coalesce threshold (queue len 1 of cap 2).
+	sub.enqueue(Sequenced{Seq: 1, Event: events.NewActivityDeltaEvent("m1", "PLAN", patchA)})
+	sub.enqueue(Sequenced{Seq: 2, Event: events.NewActivityDeltaEvent("m1", "PLAN", patchB)})
+
+	entry, ok := sub.Next()
+	require.True(t, ok)
+	delta, ok := entry.Event.(*events.ActivityDeltaEvent)
+	require.True(t, ok)
+	assert.Len(t, delta.Patch, 2)
+	assert.Equal(t, uint64(2), entry.Seq)
+
+	_, ok = sub.Next()
+	assert.False(t, ok)
+}
+
+func TestEventBus_ReplaySinceReturnsEventsAfterSeq(t *testing.T) {
+	bus := NewEventBus(16)
+	bus.Publish(events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"v": 1}))
+	bus.Publish(events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"v": 2}))
+	bus.Publish(events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"v": 3}))
+
+	replayed := bus.ReplaySince(1, Filter{})
+	require.Len(t, replayed, 2)
+	assert.Equal(t, uint64(2), replayed[0].Seq)
+	assert.Equal(t, uint64(3), replayed[1].Seq)
+}
+
+func TestEventBus_SubscribeFromExcludesBacklogFromLiveDelivery(t *testing.T) {
+	bus := NewEventBus(16)
+	bus.Publish(events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"v": 1}))
+
+	// Everything retained so far (seq 1) should come back as backlog and
+	// not also be queued for live delivery to the new subscription.
+	sub, backlog := bus.SubscribeFrom(Filter{}, 8, 0)
+	defer sub.Close()
+	require.Len(t, backlog, 1)
+	assert.Equal(t, uint64(1), backlog[0].Seq)
+
+	bus.Publish(events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"v": 2}))
+	waitReady(t, sub)
+
+	entry, ok := sub.Next()
+	require.True(t, ok)
+	assert.Equal(t, uint64(2), entry.Seq)
+
+	_, ok = sub.Next()
+	assert.False(t, ok, "seq 1 must not be delivered twice")
+}
+
+func TestSSEHandler_StreamsPublishedEvents(t *testing.T) {
+	bus := NewEventBus(16)
+	server := httptest.NewServer(bus.SSEHandler(Filter{}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// Give the handler a moment to register its subscription before
+	// publishing, since subscription happens inside the handler
+	// goroutine spawned by the test HTTP client's request.
+	time.Sleep(50 * time.Millisecond)
+	bus.Publish(events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"status": "draft"}))
+
+	buf := make([]byte, 4096)
+	n, err := resp.Body.Read(buf)
+	require.NoError(t, err)
+	body := string(buf[:n])
+
+	assert.True(t, strings.Contains(body, "event: "+string(events.EventTypeActivitySnapshot)))
+	assert.True(t, strings.Contains(body, "data: "))
+}
+
+func TestSSEHandler_ReplaysFromLastEventID(t *testing.T) {
+	bus := NewEventBus(16)
+	bus.Publish(events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"v": 1}))
+	bus.Publish(events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"v": 2}))
+
+	server := httptest.NewServer(bus.SSEHandler(Filter{}))
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	buf := make([]byte, 4096)
+	n, err := resp.Body.Read(buf)
+	require.NoError(t, err)
+	body := string(buf[:n])
+
+	assert.True(t, strings.Contains(body, "id: 2"))
+	assert.False(t, strings.Contains(body, "id: 1\n"))
+}
@@ -0,0 +1,299 @@
+package transport
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// resumeWindow is how long the handler waits, right after the
+// handshake, for a client to send a resume frame before concluding none
+// is coming and proceeding with afterSeq 0.
+const resumeWindow = 100 * time.Millisecond
+
+// maxFrameSize bounds the payload length readFrame will accept. This
+// handler's only expected incoming frame is a small {"resume": <seq>}
+// text frame, so this is generous for that purpose while still ruling
+// out a malicious or buggy client's declared length driving a
+// multi-gigabyte (or, at the wire format's 64-bit length field, flatly
+// impossible) allocation.
+const maxFrameSize = 64 * 1024
+
+// websocketGUID is the fixed key defined by RFC 6455 section 1.3 used to
+// compute the Sec-WebSocket-Accept handshake response.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+const (
+	wsOpText  = 0x1
+	wsOpClose = 0x8
+	wsOpPing  = 0x9
+	wsOpPong  = 0xA
+)
+
+// wsMessage is the wire shape for every frame the WebSocket handler
+// sends: a three-element array of [msgType, topic, payload], mirroring
+// the WAMP EVENT message shape so existing WAMP-aware clients need no
+// special casing for AG-UI's own events.
+type wsMessage [3]json.RawMessage
+
+const wsMsgTypeEvent = 8 // WAMP EVENT message code
+
+// WebSocketHandler returns an http.Handler that upgrades the connection
+// to WebSocket (RFC 6455) and streams events matching filter as
+// [8, topic, payload] triples, topic being the event's type and payload
+// its JSON encoding. A client resumes a dropped connection by sending a
+// text frame of the form {"resume": <seq>} immediately after connecting,
+// causing the handler to replay from that sequence number via
+// EventBus.SubscribeFrom before switching to live delivery.
+func (b *EventBus) WebSocketHandler(filter Filter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := wsUpgrade(w, r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer conn.Close()
+
+		incoming := make(chan wsFrame)
+		done := make(chan struct{})
+		stop := make(chan struct{})
+		defer close(stop)
+		go func() {
+			defer close(done)
+			for {
+				op, payload, err := conn.readFrame()
+				if err != nil {
+					return
+				}
+				select {
+				case incoming <- wsFrame{op: op, payload: payload}:
+				case <-stop:
+					return
+				}
+			}
+		}()
+
+		var afterSeq uint64
+		select {
+		case frame := <-incoming:
+			if resume, ok := parseResumeFrame(frame); ok {
+				afterSeq = resume
+			}
+		case <-done:
+		case <-time.After(resumeWindow):
+		}
+
+		sub, backlog := b.SubscribeFrom(filter, defaultSubscriberQueueSize, afterSeq)
+		defer sub.Close()
+
+		for _, entry := range backlog {
+			if err := conn.writeEvent(entry); err != nil {
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-incoming:
+				// Any further client frames are discarded; this
+				// handler only pushes events, it does not accept
+				// further client input after the initial resume.
+			case <-sub.Ready():
+				for {
+					entry, ok := sub.Next()
+					if !ok {
+						break
+					}
+					if err := conn.writeEvent(entry); err != nil {
+						return
+					}
+				}
+			}
+		}
+	})
+}
+
+// wsFrame is a single decoded incoming frame, handed from the
+// connection's read loop to the handler goroutine over a channel.
+type wsFrame struct {
+	op      byte
+	payload []byte
+}
+
+// parseResumeFrame interprets a text frame as a resume request of the
+// form {"resume": <seq>}. Anything else (binary frame, malformed JSON)
+// is treated as "no resume requested".
+func parseResumeFrame(frame wsFrame) (uint64, bool) {
+	if frame.op != wsOpText {
+		return 0, false
+	}
+	var req struct {
+		Resume uint64 `json:"resume"`
+	}
+	if err := json.Unmarshal(frame.payload, &req); err != nil {
+		return 0, false
+	}
+	return req.Resume, true
+}
+
+// wsConn is a minimal unframer/framer for RFC 6455 text frames, enough
+// to speak the one message shape this handler needs. It intentionally
+// does not support fragmentation, extensions, or binary frames.
+type wsConn struct {
+	rw io.ReadWriteCloser
+	br *bufio.Reader
+}
+
+func (c *wsConn) Close() error {
+	return c.rw.Close()
+}
+
+func wsUpgrade(w http.ResponseWriter, r *http.Request) (*wsConn, error) {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return nil, errors.New("transport: not a websocket upgrade request")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		return nil, errors.New("transport: missing Sec-WebSocket-Key")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		return nil, errors.New("transport: connection does not support hijacking")
+	}
+	rw, _, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	accept := wsAcceptKey(key)
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + accept + "\r\n\r\n"
+	if _, err := io.WriteString(rw, response); err != nil {
+		rw.Close()
+		return nil, err
+	}
+
+	return &wsConn{rw: rw, br: bufio.NewReader(rw)}, nil
+}
+
+func wsAcceptKey(key string) string {
+	h := sha1.New()
+	io.WriteString(h, key+websocketGUID)
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+func (c *wsConn) writeEvent(entry Sequenced) error {
+	payload, err := entry.Event.ToJSON()
+	if err != nil {
+		return err
+	}
+	topic, err := json.Marshal(entry.Event.Type())
+	if err != nil {
+		return err
+	}
+	msgType, err := json.Marshal(wsMsgTypeEvent)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(wsMessage{msgType, topic, payload})
+	if err != nil {
+		return err
+	}
+	return c.writeFrame(wsOpText, body)
+}
+
+// readFrame reads a single, unmasked-on-write / masked-on-read RFC 6455
+// frame and returns its opcode and payload. It does not reassemble
+// fragmented messages, which this handler's client contract never
+// produces.
+func (c *wsConn) readFrame() (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(c.br, header); err != nil {
+		return 0, nil, err
+	}
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(c.br, ext); err != nil {
+			return 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(c.br, ext); err != nil {
+			return 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	if length > maxFrameSize {
+		return 0, nil, fmt.Errorf("transport: frame length %d exceeds max %d", length, maxFrameSize)
+	}
+
+	var maskKey [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, maskKey[:]); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= maskKey[i%4]
+		}
+	}
+
+	if opcode == wsOpClose {
+		return opcode, payload, errors.New("transport: websocket closed by peer")
+	}
+	return opcode, payload, nil
+}
+
+// writeFrame writes a single, unmasked frame, as RFC 6455 requires of a
+// server (only clients mask their frames).
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	var header []byte
+	header = append(header, 0x80|opcode)
+
+	switch {
+	case len(payload) <= 125:
+		header = append(header, byte(len(payload)))
+	case len(payload) <= 0xFFFF:
+		header = append(header, 126)
+		ext := make([]byte, 2)
+		binary.BigEndian.PutUint16(ext, uint16(len(payload)))
+		header = append(header, ext...)
+	default:
+		header = append(header, 127)
+		ext := make([]byte, 8)
+		binary.BigEndian.PutUint64(ext, uint64(len(payload)))
+		header = append(header, ext...)
+	}
+
+	if _, err := c.rw.Write(header); err != nil {
+		return err
+	}
+	_, err := c.rw.Write(payload)
+	return err
+}
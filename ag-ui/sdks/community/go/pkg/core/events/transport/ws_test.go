@@ -0,0 +1,136 @@
+package transport
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+)
+
+// testWSClient is a bare-bones RFC 6455 client sufficient to exercise
+// WebSocketHandler: it performs the handshake and can read the unmasked
+// text frames the handler emits. It is deliberately minimal and lives
+// only in this test.
+type testWSClient struct {
+	conn net.Conn
+	br   *bufio.Reader
+}
+
+func dialTestWS(t *testing.T, url string) *testWSClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", url[len("http://"):])
+	require.NoError(t, err)
+
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789012345"))
+	req := "GET / HTTP/1.1\r\n" +
+		"Host: " + url[len("http://"):] + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+	_, err = conn.Write([]byte(req))
+	require.NoError(t, err)
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	require.NoError(t, err)
+	require.Equal(t, 101, resp.StatusCode)
+
+	expected := wsAcceptKey(key)
+	assert.Equal(t, expected, resp.Header.Get("Sec-WebSocket-Accept"))
+
+	return &testWSClient{conn: conn, br: br}
+}
+
+func (c *testWSClient) readTextFrame(t *testing.T) []byte {
+	t.Helper()
+	header := make([]byte, 2)
+	_, err := io.ReadFull(c.br, header)
+	require.NoError(t, err)
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		_, err := io.ReadFull(c.br, ext)
+		require.NoError(t, err)
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		_, err := io.ReadFull(c.br, ext)
+		require.NoError(t, err)
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	payload := make([]byte, length)
+	_, err = io.ReadFull(c.br, payload)
+	require.NoError(t, err)
+	return payload
+}
+
+func (c *testWSClient) Close() {
+	c.conn.Close()
+}
+
+func TestWSAcceptKey_MatchesRFC6455Example(t *testing.T) {
+	// The worked example from RFC 6455 section 1.3.
+	got := wsAcceptKey("dGhlIHNhbXBsZSBub25jZQ==")
+	assert.Equal(t, "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", got)
+
+	h := sha1.New()
+	h.Write([]byte("dGhlIHNhbXBsZSBub25jZQ==" + websocketGUID))
+	assert.Equal(t, base64.StdEncoding.EncodeToString(h.Sum(nil)), got)
+}
+
+func TestWSConnReadFrame_RejectsFrameOverMaxSize(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+
+	conn := &wsConn{rw: server, br: bufio.NewReader(server)}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Declared length is the 64-bit extended-length marker's max
+		// value; a naive make([]byte, length) would try to allocate
+		// ~16 exabytes (or just panic outright).
+		header := []byte{0x81, 127, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+		_, err := client.Write(header)
+		assert.NoError(t, err)
+	}()
+
+	_, _, err := conn.readFrame()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds max")
+	<-done
+}
+
+func TestWebSocketHandler_StreamsPublishedEvents(t *testing.T) {
+	bus := NewEventBus(16)
+	server := httptest.NewServer(bus.WebSocketHandler(Filter{}))
+	defer server.Close()
+
+	client := dialTestWS(t, server.URL)
+	defer client.Close()
+
+	time.Sleep(50 * time.Millisecond)
+	bus.Publish(events.NewActivitySnapshotEvent("m1", "PLAN", map[string]any{"status": "draft"}))
+
+	client.conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	payload := client.readTextFrame(t)
+
+	assert.Contains(t, string(payload), string(events.EventTypeActivitySnapshot))
+	assert.Contains(t, string(payload), "draft")
+}
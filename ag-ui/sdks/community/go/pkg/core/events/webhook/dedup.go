@@ -0,0 +1,59 @@
+package webhook
+
+import (
+	"sync"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+)
+
+// SnapshotDeduplicator suppresses repeat deliveries of an
+// ActivitySnapshotEvent to the same target when its content is
+// unchanged, using a JCS canonical-form hash of MessageID, ActivityType,
+// Content and Replace (see snapshotContentKey) rather than struct
+// equality, so that
+// re-marshaling by an intermediary can't cause a spurious redelivery.
+type SnapshotDeduplicator struct {
+	mu   sync.Mutex
+	seen map[string]string // targetID -> last seen content ID
+}
+
+// NewSnapshotDeduplicator creates an empty deduplicator.
+func NewSnapshotDeduplicator() *SnapshotDeduplicator {
+	return &SnapshotDeduplicator{seen: make(map[string]string)}
+}
+
+// snapshotContentKey is the part of an ActivitySnapshotEvent that
+// determines whether two snapshots are duplicates. It deliberately
+// excludes BaseEvent: ActivitySnapshotEvent.ContentID hashes the whole
+// event, and BaseEvent carries a per-construction timestamp, so two independently
+// built snapshots with identical Content would otherwise never compare
+// equal.
+type snapshotContentKey struct {
+	MessageID    string `json:"messageId"`
+	ActivityType string `json:"activityType"`
+	Content      any    `json:"content"`
+	Replace      *bool  `json:"replace,omitempty"`
+}
+
+// Seen records event for targetID and reports whether it is a duplicate
+// of the last snapshot delivered to that target.
+func (d *SnapshotDeduplicator) Seen(targetID string, event *events.ActivitySnapshotEvent) (bool, error) {
+	id, err := events.ContentID(snapshotContentKey{
+		MessageID:    event.MessageID,
+		ActivityType: event.ActivityType,
+		Content:      event.Content,
+		Replace:      event.Replace,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.seen[targetID] == id {
+		return true, nil
+	}
+	d.seen[targetID] = id
+	return false, nil
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+)
+
+func TestSnapshotDeduplicator_SuppressesUnchangedContent(t *testing.T) {
+	dedup := NewSnapshotDeduplicator()
+	event := events.NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "draft"})
+
+	dup, err := dedup.Seen("t1", event)
+	require.NoError(t, err)
+	assert.False(t, dup)
+
+	dup, err = dedup.Seen("t1", event)
+	require.NoError(t, err)
+	assert.True(t, dup)
+}
+
+func TestSnapshotDeduplicator_SuppressesUnchangedContentAcrossDistinctEvents(t *testing.T) {
+	dedup := NewSnapshotDeduplicator()
+	first := events.NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "draft"})
+	second := events.NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "draft"})
+
+	// first and second are distinct *ActivitySnapshotEvent values with
+	// their own BaseEvent (and thus their own timestamp), so this only
+	// passes if dedup hashes Content rather than the whole event.
+	dup, err := dedup.Seen("t1", first)
+	require.NoError(t, err)
+	assert.False(t, dup)
+
+	dup, err = dedup.Seen("t1", second)
+	require.NoError(t, err)
+	assert.True(t, dup)
+}
+
+func TestSnapshotDeduplicator_ChangedContentIsNotDuplicate(t *testing.T) {
+	dedup := NewSnapshotDeduplicator()
+	first := events.NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "draft"})
+	second := events.NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "done"})
+
+	_, err := dedup.Seen("t1", first)
+	require.NoError(t, err)
+
+	dup, err := dedup.Seen("t1", second)
+	require.NoError(t, err)
+	assert.False(t, dup)
+}
+
+func TestSnapshotDeduplicator_ReplaceChangeIsNotDuplicate(t *testing.T) {
+	dedup := NewSnapshotDeduplicator()
+	first := events.NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "draft"})
+	second := events.NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "draft"}).WithReplace(false)
+
+	_, err := dedup.Seen("t1", first)
+	require.NoError(t, err)
+
+	dup, err := dedup.Seen("t1", second)
+	require.NoError(t, err)
+	assert.False(t, dup, "a snapshot with the same content but a different Replace semantics must not be suppressed")
+}
+
+func TestSnapshotDeduplicator_TracksPerTarget(t *testing.T) {
+	dedup := NewSnapshotDeduplicator()
+	event := events.NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "draft"})
+
+	_, err := dedup.Seen("t1", event)
+	require.NoError(t, err)
+
+	dup, err := dedup.Seen("t2", event)
+	require.NoError(t, err)
+	assert.False(t, dup)
+}
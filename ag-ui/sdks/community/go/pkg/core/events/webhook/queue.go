@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Queue stores delivery tasks awaiting a worker pull. The default
+// implementation, MemoryQueue, is a process-local task queue; a
+// production deployment would back this with a durable store but the
+// interface only needs enqueue/lease/complete semantics.
+type Queue interface {
+	// Enqueue persists task for later delivery.
+	Enqueue(task *Task) error
+	// Lease returns up to n tasks that are due (NextAttemptAt has
+	// passed), not already delivered, not dead (retries exhausted),
+	// and not already leased to an earlier, still-outstanding Lease
+	// call. A task stays leased until Complete or Retry is called for
+	// its ID, so two overlapping Lease calls never return the same
+	// task.
+	Lease(n int) ([]*Task, error)
+	// Complete records the outcome of a delivery attempt for taskID.
+	Complete(taskID string, succeeded bool, deliveryErr error) error
+	// Retry forces taskID to become immediately due again, regardless
+	// of its current backoff schedule.
+	Retry(taskID string) error
+}
+
+// MemoryQueue is an in-memory Queue implementation.
+type MemoryQueue struct {
+	mu     sync.Mutex
+	tasks  map[string]*Task
+	leased map[string]bool
+}
+
+// NewMemoryQueue creates an empty in-memory queue.
+func NewMemoryQueue() *MemoryQueue {
+	return &MemoryQueue{
+		tasks:  make(map[string]*Task),
+		leased: make(map[string]bool),
+	}
+}
+
+// Enqueue implements Queue.
+func (q *MemoryQueue) Enqueue(task *Task) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if task.ID == "" {
+		return fmt.Errorf("webhook: task id is required")
+	}
+	q.tasks[task.ID] = task
+	return nil
+}
+
+// Lease implements Queue.
+func (q *MemoryQueue) Lease(n int) ([]*Task, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	now := time.Now()
+	due := make([]*Task, 0, n)
+	for id, task := range q.tasks {
+		if task.IsDelivered || task.IsDead || task.NextAttemptAt.After(now) || q.leased[id] {
+			continue
+		}
+		q.leased[id] = true
+		due = append(due, task)
+		if len(due) == n {
+			break
+		}
+	}
+	return due, nil
+}
+
+// Complete implements Queue.
+func (q *MemoryQueue) Complete(taskID string, succeeded bool, deliveryErr error) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	task, ok := q.tasks[taskID]
+	if !ok {
+		return fmt.Errorf("webhook: unknown task %q", taskID)
+	}
+	delete(q.leased, taskID)
+
+	task.Attempts++
+	task.IsSucceed = succeeded
+	if deliveryErr != nil {
+		task.LastError = deliveryErr.Error()
+	} else {
+		task.LastError = ""
+	}
+	if succeeded {
+		task.IsDelivered = true
+	}
+	return nil
+}
+
+// Retry implements Queue.
+func (q *MemoryQueue) Retry(taskID string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	task, ok := q.tasks[taskID]
+	if !ok {
+		return fmt.Errorf("webhook: unknown task %q", taskID)
+	}
+	delete(q.leased, taskID)
+
+	task.IsDelivered = false
+	task.IsDead = false
+	task.NextAttemptAt = time.Now()
+	return nil
+}
@@ -0,0 +1,38 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// genericEnvelope is the body shape used by FormatGeneric.
+type genericEnvelope struct {
+	EventType string          `json:"eventType"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// Render builds the outbound request body for task according to target's
+// format, switching on the task's PayloadVersion to decide whether the
+// body still needs to be rendered from a raw event.
+func Render(task *Task, target Target) ([]byte, error) {
+	if task.PayloadVersion == PayloadVersionRendered {
+		// Pre-refactor task: RawPayload is already the request body.
+		return task.RawPayload, nil
+	}
+
+	switch target.Format {
+	case "", FormatNative:
+		return task.RawPayload, nil
+	case FormatGeneric:
+		body, err := json.Marshal(genericEnvelope{
+			EventType: string(task.EventType),
+			Payload:   json.RawMessage(task.RawPayload),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("webhook: render generic envelope: %w", err)
+		}
+		return body, nil
+	default:
+		return nil, fmt.Errorf("webhook: unknown target format %q", target.Format)
+	}
+}
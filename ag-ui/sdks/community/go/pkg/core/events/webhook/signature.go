@@ -0,0 +1,25 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// SignatureHeader is the HTTP header carrying the delivery signature.
+const SignatureHeader = "X-Signature"
+
+// Sign computes an HMAC-SHA256 signature over body using the target's
+// secret, hex-encoded with a "sha256=" prefix so verifiers can identify
+// the algorithm without a side channel.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether sig is the correct signature for body
+// under secret, using a constant-time comparison.
+func VerifySignature(secret, body []byte, sig string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(sig))
+}
@@ -0,0 +1,107 @@
+// Package webhook delivers AG-UI events to subscriber-configured HTTP
+// endpoints. An event is persisted verbatim into a task queue alongside a
+// PayloadVersion, and a worker later renders that raw payload into an
+// http.Request according to the target's format (native JSON, generic
+// webhook, etc). Keeping event-type-specific logic (what the payload
+// means) separate from delivery-time logic (how it is rendered and
+// signed) means adding a new event kind only requires a renderer, not
+// changes to the queue or worker.
+package webhook
+
+import (
+	"time"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+)
+
+// PayloadVersion identifies the shape of a Task's RawPayload so that
+// pre-refactor tasks (already-rendered bodies) can still be replayed
+// alongside newer raw-event tasks.
+type PayloadVersion int
+
+const (
+	// PayloadVersionRendered marks tasks whose RawPayload is already a
+	// fully rendered, target-specific request body produced before this
+	// package persisted raw events.
+	PayloadVersionRendered PayloadVersion = iota
+	// PayloadVersionRawEvent marks tasks whose RawPayload is the
+	// verbatim marshaled event; the body is rendered at delivery time.
+	PayloadVersionRawEvent
+)
+
+// Format selects how a Task's raw payload is rendered into an outbound
+// request body for a given Target.
+type Format string
+
+const (
+	// FormatNative renders the event using its own JSON encoding.
+	FormatNative Format = "native"
+	// FormatGeneric wraps the event in a minimal, target-agnostic
+	// envelope: {"eventType": ..., "payload": <event>}.
+	FormatGeneric Format = "generic"
+)
+
+// Target describes a webhook subscriber: where to deliver events, which
+// event types it wants, and how to render and sign the body.
+type Target struct {
+	ID          string
+	URL         string
+	Format      Format
+	EventTypes  []events.EventType // empty means "all event types"
+	Secret      []byte             // HMAC key for the X-Signature header
+	MaxRetries  int
+	BaseBackoff time.Duration
+}
+
+// Subscribes reports whether the target wants deliveries for et.
+func (t Target) Subscribes(et events.EventType) bool {
+	if len(t.EventTypes) == 0 {
+		return true
+	}
+	for _, want := range t.EventTypes {
+		if want == et {
+			return true
+		}
+	}
+	return false
+}
+
+// Task is a single queued delivery attempt for one event to one target.
+type Task struct {
+	ID             string
+	TargetID       string
+	EventType      events.EventType
+	PayloadVersion PayloadVersion
+	// RawPayload is the verbatim marshaled event for
+	// PayloadVersionRawEvent tasks, or the already-rendered request
+	// body for PayloadVersionRendered tasks.
+	RawPayload []byte
+
+	Attempts    int
+	IsDelivered bool
+	IsSucceed   bool
+	// IsDead is set once Attempts exhausts the target's MaxRetries
+	// without a successful delivery. Dead tasks are never leased again;
+	// Retry clears the flag to force another attempt.
+	IsDead        bool
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+// NewTask creates a raw-event delivery task for target using the event's
+// own JSON encoding as the raw payload.
+func NewTask(id string, target Target, event events.Event) (*Task, error) {
+	raw, err := event.ToJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Task{
+		ID:             id,
+		TargetID:       target.ID,
+		EventType:      event.Type(),
+		PayloadVersion: PayloadVersionRawEvent,
+		RawPayload:     raw,
+		NextAttemptAt:  time.Now(),
+	}, nil
+}
@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ag-ui-protocol/ag-ui/sdks/community/go/pkg/core/events"
+)
+
+func TestTargetSubscribes(t *testing.T) {
+	all := Target{}
+	assert.True(t, all.Subscribes(events.EventTypeActivitySnapshot))
+
+	scoped := Target{EventTypes: []events.EventType{events.EventTypeActivityDelta}}
+	assert.True(t, scoped.Subscribes(events.EventTypeActivityDelta))
+	assert.False(t, scoped.Subscribes(events.EventTypeActivitySnapshot))
+}
+
+func TestNewTaskUsesRawEventPayload(t *testing.T) {
+	event := events.NewActivitySnapshotEvent("activity-1", "PLAN", map[string]any{"status": "draft"})
+	target := Target{ID: "t1"}
+
+	task, err := NewTask("task-1", target, event)
+	require.NoError(t, err)
+	assert.Equal(t, PayloadVersionRawEvent, task.PayloadVersion)
+	assert.Equal(t, events.EventTypeActivitySnapshot, task.EventType)
+	assert.NotEmpty(t, task.RawPayload)
+}
+
+func TestRenderNativeReturnsRawPayload(t *testing.T) {
+	task := &Task{PayloadVersion: PayloadVersionRawEvent, RawPayload: []byte(`{"a":1}`)}
+
+	body, err := Render(task, Target{Format: FormatNative})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"a":1}`, string(body))
+}
+
+func TestRenderGenericWrapsEnvelope(t *testing.T) {
+	task := &Task{
+		PayloadVersion: PayloadVersionRawEvent,
+		EventType:      events.EventTypeActivitySnapshot,
+		RawPayload:     []byte(`{"a":1}`),
+	}
+
+	body, err := Render(task, Target{Format: FormatGeneric})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"eventType":"ACTIVITY_SNAPSHOT","payload":{"a":1}}`, string(body))
+}
+
+func TestRenderRenderedPayloadVersionSkipsRewrite(t *testing.T) {
+	task := &Task{PayloadVersion: PayloadVersionRendered, RawPayload: []byte(`already-rendered`)}
+
+	body, err := Render(task, Target{Format: FormatGeneric})
+	require.NoError(t, err)
+	assert.Equal(t, "already-rendered", string(body))
+}
+
+func TestSignAndVerifySignature(t *testing.T) {
+	secret := []byte("shh")
+	body := []byte(`{"a":1}`)
+
+	sig := Sign(secret, body)
+	assert.True(t, VerifySignature(secret, body, sig))
+	assert.False(t, VerifySignature(secret, []byte(`{"a":2}`), sig))
+}
+
+func TestWorkerDeliversAndMarksSucceeded(t *testing.T) {
+	var gotSig string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSig = r.Header.Get(SignatureHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	target := Target{ID: "t1", URL: server.URL, Secret: []byte("shh")}
+	queue := NewMemoryQueue()
+	require.NoError(t, queue.Enqueue(&Task{ID: "task-1", TargetID: "t1", RawPayload: []byte(`{"a":1}`)}))
+
+	worker := NewWorker(queue, map[string]Target{"t1": target})
+	n, err := worker.RunOnce(10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.NotEmpty(t, gotSig)
+
+	leased, err := queue.Lease(10)
+	require.NoError(t, err)
+	assert.Empty(t, leased)
+	assert.True(t, queue.tasks["task-1"].IsDelivered)
+	assert.True(t, queue.tasks["task-1"].IsSucceed)
+}
+
+func TestWorkerSchedulesExponentialBackoffOnFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	target := Target{ID: "t1", URL: server.URL, MaxRetries: 3, BaseBackoff: time.Second}
+	queue := NewMemoryQueue()
+	require.NoError(t, queue.Enqueue(&Task{ID: "task-1", TargetID: "t1", RawPayload: []byte(`{}`)}))
+
+	worker := NewWorker(queue, map[string]Target{"t1": target})
+	_, err := worker.RunOnce(10)
+	require.NoError(t, err)
+
+	task := queue.tasks["task-1"]
+	assert.False(t, task.IsDelivered)
+	assert.False(t, task.IsSucceed)
+	assert.True(t, task.NextAttemptAt.After(time.Now()))
+}
+
+func TestWorkerMarksTaskDeadAfterMaxRetries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	target := Target{ID: "t1", URL: server.URL, MaxRetries: 2, BaseBackoff: time.Millisecond}
+	queue := NewMemoryQueue()
+	require.NoError(t, queue.Enqueue(&Task{ID: "task-1", TargetID: "t1", RawPayload: []byte(`{}`)}))
+
+	worker := NewWorker(queue, map[string]Target{"t1": target})
+	for i := 0; i < 2; i++ {
+		_, err := worker.RunOnce(10)
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	task := queue.tasks["task-1"]
+	require.False(t, task.IsDead, "task should not be dead before MaxRetries is reached")
+
+	// One more attempt exhausts MaxRetries.
+	_, err := worker.RunOnce(10)
+	require.NoError(t, err)
+	assert.True(t, task.IsDead)
+	assert.False(t, task.IsDelivered)
+
+	leased, err := queue.Lease(10)
+	require.NoError(t, err)
+	assert.Empty(t, leased, "a dead task must never be leased again, regardless of NextAttemptAt")
+}
+
+func TestLeaseExcludesAlreadyLeasedTask(t *testing.T) {
+	queue := NewMemoryQueue()
+	require.NoError(t, queue.Enqueue(&Task{ID: "task-1", TargetID: "t1"}))
+
+	first, err := queue.Lease(10)
+	require.NoError(t, err)
+	require.Len(t, first, 1, "first lease should pick up the due task")
+
+	second, err := queue.Lease(10)
+	require.NoError(t, err)
+	assert.Empty(t, second, "a task already leased and not yet completed must not be leased again")
+
+	require.NoError(t, queue.Complete("task-1", true, nil))
+	third, err := queue.Lease(10)
+	require.NoError(t, err)
+	assert.Empty(t, third, "a delivered task stays excluded, lease or not")
+}
+
+func TestRetryForcesImmediateRedelivery(t *testing.T) {
+	queue := NewMemoryQueue()
+	require.NoError(t, queue.Enqueue(&Task{
+		ID:            "task-1",
+		TargetID:      "t1",
+		IsDelivered:   true,
+		NextAttemptAt: time.Now().Add(time.Hour),
+	}))
+
+	worker := NewWorker(queue, map[string]Target{"t1": {ID: "t1"}})
+	require.NoError(t, worker.Retry("task-1"))
+
+	leased, err := queue.Lease(10)
+	require.NoError(t, err)
+	require.Len(t, leased, 1)
+	assert.Equal(t, "task-1", leased[0].ID)
+}
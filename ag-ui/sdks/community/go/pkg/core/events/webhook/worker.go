@@ -0,0 +1,113 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+)
+
+// defaultMaxRetries and defaultBaseBackoff are used for targets that
+// leave MaxRetries/BaseBackoff unset.
+const (
+	defaultMaxRetries  = 5
+	defaultBaseBackoff = time.Second
+)
+
+// Worker pulls due tasks from a Queue and delivers them to their Target.
+type Worker struct {
+	Queue   Queue
+	Targets map[string]Target
+	Client  *http.Client
+}
+
+// NewWorker creates a Worker that delivers tasks pulled from queue to the
+// given targets, keyed by Target.ID.
+func NewWorker(queue Queue, targets map[string]Target) *Worker {
+	return &Worker{
+		Queue:   queue,
+		Targets: targets,
+		Client:  http.DefaultClient,
+	}
+}
+
+// Retry requeues taskID for immediate redelivery.
+func (w *Worker) Retry(taskID string) error {
+	return w.Queue.Retry(taskID)
+}
+
+// RunOnce leases up to n due tasks and attempts to deliver each. It
+// returns the number of tasks it attempted.
+func (w *Worker) RunOnce(n int) (int, error) {
+	tasks, err := w.Queue.Lease(n)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, task := range tasks {
+		w.deliver(task)
+	}
+	return len(tasks), nil
+}
+
+func (w *Worker) deliver(task *Task) {
+	target, ok := w.Targets[task.TargetID]
+	if !ok {
+		w.Queue.Complete(task.ID, false, fmt.Errorf("webhook: unknown target %q", task.TargetID))
+		return
+	}
+
+	body, err := Render(task, target)
+	if err != nil {
+		w.Queue.Complete(task.ID, false, err)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		w.Queue.Complete(task.ID, false, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(target.Secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(target.Secret, body))
+	}
+
+	resp, err := w.Client.Do(req)
+	if err != nil {
+		w.scheduleRetry(task, target, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		w.scheduleRetry(task, target, fmt.Errorf("webhook: target responded %d", resp.StatusCode))
+		return
+	}
+
+	w.Queue.Complete(task.ID, true, nil)
+}
+
+// scheduleRetry records the failed attempt and, if retries remain,
+// reschedules the task with exponential backoff: BaseBackoff * 2^attempt.
+func (w *Worker) scheduleRetry(task *Task, target Target, deliveryErr error) {
+	w.Queue.Complete(task.ID, false, deliveryErr)
+
+	maxRetries := target.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+	if task.Attempts > maxRetries {
+		task.IsDead = true
+		return
+	}
+
+	baseBackoff := target.BaseBackoff
+	if baseBackoff <= 0 {
+		baseBackoff = defaultBaseBackoff
+	}
+
+	backoff := time.Duration(float64(baseBackoff) * math.Pow(2, float64(task.Attempts-1)))
+	task.NextAttemptAt = time.Now().Add(backoff)
+}